@@ -16,10 +16,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -27,14 +29,22 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/alecthomas/kong"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	xpextv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	xppkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/upbound/extensions-migration/pkg/converter/configuration"
+	"github.com/upbound/extensions-migration/pkg/xpkg"
 )
 
 const (
@@ -53,8 +63,13 @@ const (
 	providerAzureChoice = "provider-azure"
 	providerGcpChoice   = "provider-gcp"
 
-	configurationMode = "configuration"
-	justMrMode        = "managed"
+	configurationMode       = "configuration"
+	justMrMode              = "managed"
+	clusterCompositionsMode = "cluster-compositions"
+
+	revertCompositionsStep = "revert-compositions"
+
+	packageLockName = "lock"
 )
 
 var monolithicToFamily = map[string]string{
@@ -73,12 +88,17 @@ type Options struct {
 			PackageRoot   string `name:"package-root" help:"Source directory for the Crossplane Configuration package." survey:"package-root"`
 			ExamplesRoot  string `name:"examples-root" help:"Path to Crossplane package examples directory." survey:"examples-root"`
 			PackageOutput string `name:"package-output" help:"Path to store the updated configuration package." survey:"package-output"`
+			OCILayout     string `name:"oci-layout" help:"If set, the updated configuration package is written to the OCI image layout at this directory instead of being pushed to the registry."`
+
+			ConfigurationMapping string `name:"configuration-mapping" help:"Path to the file mapping the nested Configuration packages to their migration targets."`
 		} `kong:"cmd"`
 
 		Managed struct {
 			resourcePath string
 		} `kong:"cmd"`
 
+		ClusterCompositions struct{} `kong:"cmd" help:"Migrate the managed resources, Compositions, composites and claims in a cluster without a Configuration package."`
+
 		RegistryOrg        string `name:"regorg" help:"<registry host>/<organization> for the provider family packages."`
 		AWSFamilyVersion   string `name:"aws-family-version" help:"Version of the AWS provider family."`
 		AzureFamilyVersion string `name:"azure-family-version" help:"Version of the Azure provider family."`
@@ -124,24 +144,33 @@ func main() {
 func generatePlan(kongCtx *kong.Context, opts *Options, planDir string, mode string) {
 	r := migration.NewRegistry(runtime.NewScheme())
 
+	if len(opts.Generate.KubeConfig) == 0 {
+		homeDir, err := os.UserHomeDir()
+		kongCtx.FatalIfErrorf(err, "Failed to get user's home")
+		opts.Generate.KubeConfig = filepath.Join(homeDir, defaultKubeConfig)
+	}
+
+	var nested []configuration.PackageMapping
+	var compositions *configuration.CompositionParameters
 	switch mode {
 	case configurationMode:
-		if err := registerConfigurationPackageConverters(opts, r); err != nil {
+		var err error
+		nested, err = getNestedConfigurations(opts)
+		kongCtx.FatalIfErrorf(err, "Failed to collect the nested Configuration packages")
+		if err := registerConfigurationPackageConverters(opts, r, nested); err != nil {
 			kongCtx.FatalIfErrorf(err, "Failed to register converters")
 		}
 	case justMrMode:
 		if err := registerManagedResourceConverters(opts, r); err != nil {
 			kongCtx.FatalIfErrorf(err, "Failed to register converters")
 		}
+	case clusterCompositionsMode:
+		var err error
+		compositions, err = registerClusterCompositionConverters(opts, r)
+		kongCtx.FatalIfErrorf(err, "Failed to register converters")
 	}
 
-	if len(opts.Generate.KubeConfig) == 0 {
-		homeDir, err := os.UserHomeDir()
-		kongCtx.FatalIfErrorf(err, "Failed to get user's home")
-		opts.Generate.KubeConfig = filepath.Join(homeDir, defaultKubeConfig)
-	}
-
-	sources, err := initializeSources(mode, r, opts)
+	sources, err := initializeSources(mode, r, opts, nested)
 	if err != nil {
 		kongCtx.FatalIfErrorf(err, "Failed to initialize sources")
 	}
@@ -155,8 +184,11 @@ func generatePlan(kongCtx *kong.Context, opts *Options, planDir string, mode str
 	pg := migration.NewPlanGenerator(r, nil, migration.NewFileSystemTarget(migration.WithParentDirectory(planDir)), pgOpts...)
 	kongCtx.FatalIfErrorf(pg.GeneratePlan(), "Failed to generate the migration plan for the provider families")
 
-	if mode == configurationMode {
-		setPkgParameters(&pg.Plan, *opts)
+	switch mode {
+	case configurationMode:
+		kongCtx.FatalIfErrorf(setPkgParameters(&pg.Plan, *opts, nested), "Failed to set the package build and push parameters")
+	case clusterCompositionsMode:
+		kongCtx.FatalIfErrorf(addRevertCompositionsStep(&pg.Plan, planDir, compositions), "Failed to add the step restoring the Compositions")
 	}
 
 	buff, err := yaml.Marshal(pg.Plan)
@@ -174,7 +206,7 @@ func generatePlan(kongCtx *kong.Context, opts *Options, planDir string, mode str
 	}
 }
 
-func initializeSources(mode string, r *migration.Registry, opts *Options) ([]migration.Source, error) {
+func initializeSources(mode string, r *migration.Registry, opts *Options, nested []configuration.PackageMapping) ([]migration.Source, error) {
 	kubeSource, err := migration.NewKubernetesSourceFromKubeConfig(opts.Generate.KubeConfig, migration.WithRegistry(r), migration.WithCategories([]migration.Category{migration.CategoryManaged}))
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to initialize the migration Kubernetes source from kubeconfig: %s", opts.Generate.KubeConfig)
@@ -186,11 +218,76 @@ func initializeSources(mode string, r *migration.Registry, opts *Options) ([]mig
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed to initialize the migration FileSystem source from path: %s", opts.Generate.Configuration.PackageRoot)
 		}
-		return []migration.Source{fsSource, kubeSource}, nil
+		sources = []migration.Source{fsSource}
+		for _, m := range nested {
+			if m.PackageRoot == "" {
+				continue
+			}
+			nestedSource, err := migration.NewFileSystemSource(m.PackageRoot)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to initialize the migration FileSystem source for the nested Configuration package %s from path: %s", m.Source, m.PackageRoot)
+			}
+			sources = append(sources, nestedSource)
+		}
+		return append(sources, kubeSource), nil
 	}
 	return sources, nil
 }
 
+// getNestedConfigurations walks the Configuration dependency tree of the
+// migration source Configuration package using the package lock in the
+// cluster and the package metadata, and returns the mappings for the nested
+// Configuration packages.
+func getNestedConfigurations(opts *Options) ([]configuration.PackageMapping, error) {
+	if opts.Generate.Configuration.ConfigurationMapping == "" {
+		return nil, nil
+	}
+	mappings, err := configuration.ReadPackageMappings(opts.Generate.Configuration.ConfigurationMapping)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := getPackageLock(opts.Generate.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	return configuration.NestedConfigurations(configuration.PackageMapping{
+		Source:      opts.Generate.Configuration.SourceConfigurationPackage,
+		Target:      opts.Generate.Configuration.TargetConfigurationPackage,
+		PackageRoot: opts.Generate.Configuration.PackageRoot,
+	}, lock, mappings)
+}
+
+func newKubeClient(kubeconfig string) (client.Client, error) {
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to load the kubeconfig: %s", kubeconfig)
+	}
+	s := runtime.NewScheme()
+	if err := xppkgv1beta1.AddToScheme(s); err != nil {
+		return nil, errors.Wrap(err, "Failed to register the Crossplane package types")
+	}
+	if err := xpextv1.AddToScheme(s); err != nil {
+		return nil, errors.Wrap(err, "Failed to register the Crossplane apiextensions types")
+	}
+	c, err := client.New(cfg, client.Options{Scheme: s})
+	return c, errors.Wrap(err, "Failed to initialize the Kubernetes client")
+}
+
+func getPackageLock(kubeconfig string) (*xppkgv1beta1.Lock, error) {
+	c, err := newKubeClient(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	lock := &xppkgv1beta1.Lock{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: packageLockName}, lock); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Failed to get the package lock: %s", packageLockName)
+	}
+	return lock, nil
+}
+
 func executePlan(kongCtx *kong.Context, planDir string, opts *Options) {
 	plan := &migration.Plan{}
 	buff, err := os.ReadFile(opts.PlanPath)
@@ -200,7 +297,9 @@ func executePlan(kongCtx *kong.Context, planDir string, opts *Options) {
 	stepByStep := askExecutionSteps(kongCtx, plan, opts, planDir)
 	zl := zap.New(zap.UseDevMode(opts.Debug))
 	log := logging.NewLogrLogger(zl.WithName("fork-executor"))
-	executor := migration.NewForkExecutor(migration.WithWorkingDir(planDir), migration.WithLogger(log))
+	// the package build & push steps are executed in-process and the rest
+	// are delegated to the fork executor.
+	executor := xpkg.NewExecutor(migration.NewForkExecutor(migration.WithWorkingDir(planDir), migration.WithLogger(log)), xpkg.WithWorkingDir(planDir))
 	// TODO: we need to load the plan back from the filesystem as it may
 	// have been modified.
 	var cb migration.ExecutorCallback
@@ -219,20 +318,79 @@ func executePlan(kongCtx *kong.Context, planDir string, opts *Options) {
 	kongCtx.FatalIfErrorf(planExecutor.Execute(), "Failed to execute the migration plan at path: %s", opts.PlanPath)
 }
 
-func setPkgParameters(plan *migration.Plan, opts Options) {
-	for i, s := range plan.Spec.Steps {
+func setPkgParameters(plan *migration.Plan, opts Options, nested []configuration.PackageMapping) error {
+	c := opts.Generate.Configuration
+	var pushTemplate migration.Step
+	for _, s := range plan.Spec.Steps {
+		if s.Name == "push-configuration" {
+			pushTemplate = s
+		}
+	}
+	steps := make([]migration.Step, 0, len(plan.Spec.Steps)+2*len(nested))
+	for _, s := range plan.Spec.Steps {
 		// TODO: consider exporting step constants. But the idea is
 		// to introduce the concept of a migration Scenario that
 		// encapsulated both the converters and the steps involved.
-		if s.Name == "push-configuration" || s.Name == "build-configuration" {
-			s.Exec.Args[1] = strings.ReplaceAll(s.Exec.Args[1], "{{TARGET_CONFIGURATION_PACKAGE}}", opts.Generate.Configuration.TargetConfigurationPackage)
-			s.Exec.Args[1] = strings.ReplaceAll(s.Exec.Args[1], "{{PKG_PATH}}", opts.Generate.Configuration.PackageOutput)
-			s.Exec.Args[1] = strings.ReplaceAll(s.Exec.Args[1], "{{PKG_ROOT}}", opts.Generate.Configuration.PackageRoot)
-			s.Exec.Args[1] = strings.ReplaceAll(s.Exec.Args[1], "{{EXAMPLES_ROOT}}", opts.Generate.Configuration.ExamplesRoot)
-			migration.AddManualExecution(&s)
-			plan.Spec.Steps[i] = s
+		switch s.Name {
+		case "build-configuration":
+			// the nested Configuration packages are built and pushed
+			// before the root package depending on them.
+			for _, m := range nested {
+				if m.PackageRoot == "" {
+					continue
+				}
+				pkgName, err := packageName(m.Target)
+				if err != nil {
+					return err
+				}
+				pkgPath := filepath.Join(filepath.Dir(c.PackageOutput), pkgName+".pkg")
+				steps = append(steps,
+					pkgStep(s, fmt.Sprintf("%s-%s", s.Name, pkgName), m.PackageRoot, "", pkgPath, m.Target, c.OCILayout),
+					pkgStep(pushTemplate, fmt.Sprintf("%s-%s", pushTemplate.Name, pkgName), m.PackageRoot, "", pkgPath, m.Target, c.OCILayout))
+			}
+			steps = append(steps, pkgStep(s, s.Name, c.PackageRoot, c.ExamplesRoot, c.PackageOutput, c.TargetConfigurationPackage, c.OCILayout))
+		case "push-configuration":
+			steps = append(steps, pkgStep(s, s.Name, c.PackageRoot, c.ExamplesRoot, c.PackageOutput, c.TargetConfigurationPackage, c.OCILayout))
+		default:
+			steps = append(steps, s)
 		}
 	}
+	plan.Spec.Steps = steps
+	return nil
+}
+
+// pkgStep returns a native package build or push step with the given name
+// from the given template step for the specified Configuration package.
+func pkgStep(template migration.Step, stepName, pkgRoot, examplesRoot, pkgPath, ref, ociLayout string) migration.Step {
+	s := template
+	s.Name = stepName
+	s.Exec = &migration.ExecStep{
+		Command: template.Exec.Command,
+		Args:    append([]string(nil), template.Exec.Args...),
+	}
+	s.Exec.Args[1] = strings.ReplaceAll(s.Exec.Args[1], "{{TARGET_CONFIGURATION_PACKAGE}}", ref)
+	s.Exec.Args[1] = strings.ReplaceAll(s.Exec.Args[1], "{{PKG_PATH}}", pkgPath)
+	s.Exec.Args[1] = strings.ReplaceAll(s.Exec.Args[1], "{{PKG_ROOT}}", pkgRoot)
+	s.Exec.Args[1] = strings.ReplaceAll(s.Exec.Args[1], "{{EXAMPLES_ROOT}}", examplesRoot)
+	// the up commands are kept as the manual execution instructions
+	// while the steps themselves are executed natively.
+	migration.AddManualExecution(&s)
+	if template.Name == "build-configuration" {
+		xpkg.SetBuildStep(&s, pkgRoot, examplesRoot, pkgPath, ref)
+	} else {
+		xpkg.SetPushStep(&s, pkgPath, ref, ociLayout)
+	}
+	return s
+}
+
+// packageName returns the repository name of the given package reference,
+// e.g., platform-ref-aws-net for xpkg.upbound.io/upbound/platform-ref-aws-net:v0.1.0.
+func packageName(ref string) (string, error) {
+	r, err := name.ParseReference(ref, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to parse the package reference: %s", ref)
+	}
+	return path.Base(r.Context().RepositoryStr()), nil
 }
 
 func getGenerateInputs(kongCtx *kong.Context, planDir string, opts *Options, mode string) {
@@ -414,30 +572,159 @@ func registerManagedResourceConverters(opts *Options, r *migration.Registry) err
 	return nil
 }
 
-func registerConfigurationPackageConverters(opts *Options, r *migration.Registry) error {
+// registerClusterCompositionConverters registers the converters for
+// migrating the managed resources together with the Compositions, the
+// composites and the claims observed in the cluster. The providers are
+// computed from both the managed resources and the Compositions.
+func registerClusterCompositionConverters(opts *Options, r *migration.Registry) (*configuration.CompositionParameters, error) {
+	if err := r.AddCrossplanePackageTypes(); err != nil {
+		return nil, errors.Wrap(err, "Failed to register the Provider package types with the migration registry")
+	}
+	if err := r.AddCompositionTypes(); err != nil {
+		return nil, errors.Wrap(err, "Failed to register the Crossplane Composition types with the migration registry")
+	}
+	if err := registerCompositeTypes(opts.Generate.KubeConfig, r); err != nil {
+		return nil, err
+	}
+	mp := configuration.NewMRPreProcessor()
+	cp := configuration.NewCompositionPreProcessor()
+	r.RegisterPreProcessor(migration.CategoryManaged, migration.PreProcessor(mp.GetSSOPNameFromManagedResource))
+	r.RegisterPreProcessor(migration.CategoryComposition, migration.PreProcessor(cp.GetSSOPNameFromComposition))
+
+	compositions := &configuration.CompositionParameters{}
+	for _, f := range []struct {
+		monolith      string
+		familyVersion string
+	}{
+		{monolith: "provider-aws", familyVersion: opts.Generate.AWSFamilyVersion},
+		{monolith: "provider-azure", familyVersion: opts.Generate.AzureFamilyVersion},
+		{monolith: "provider-gcp", familyVersion: opts.Generate.GCPFamilyVersion},
+	} {
+		if f.familyVersion != "" {
+			compositions.Monoliths = append(compositions.Monoliths, f.monolith)
+		}
+		re := regexp.MustCompile(fmt.Sprintf(`xpkg.upbound.io/upbound/%s:.+`, f.monolith))
+		// register converters for the family config packages
+		r.RegisterProviderPackageConverter(re, &configuration.ProviderPkgFamilyConfigParameters{
+			FamilyVersion: f.familyVersion,
+		})
+		// register converters for the family resource packages
+		r.RegisterProviderPackageConverter(re, &configuration.ProviderPkgFamilyParameters{
+			FamilyVersion:            f.familyVersion,
+			Monolith:                 f.monolith,
+			CompositionProcessor:     cp,
+			ManagedResourceProcessor: mp,
+		})
+	}
+	r.RegisterCategoricalConverter(migration.CategoryComposition, compositions)
+	return compositions, nil
+}
+
+// registerCompositeTypes registers the composite and claim types defined by
+// the CompositeResourceDefinitions in the cluster with the migration
+// registry, so that the composites and the claims are read from the cluster.
+func registerCompositeTypes(kubeconfig string, r *migration.Registry) error {
+	c, err := newKubeClient(kubeconfig)
+	if err != nil {
+		return err
+	}
+	xrds := &xpextv1.CompositeResourceDefinitionList{}
+	if err := c.List(context.Background(), xrds); err != nil {
+		return errors.Wrap(err, "Failed to list the CompositeResourceDefinitions")
+	}
+	for _, xrd := range xrds.Items {
+		r.AddCompositeType(xrd.GetCompositeGroupVersionKind())
+		if xrd.OffersClaim() {
+			r.AddClaimType(xrd.GetClaimGroupVersionKind())
+		}
+	}
+	return nil
+}
+
+// addRevertCompositionsStep appends a step to the given plan that restores
+// the Compositions patched during the migration after the deletion
+// policies of the managed resources have been reverted.
+func addRevertCompositionsStep(plan *migration.Plan, planDir string, compositions *configuration.CompositionParameters) error {
+	patches, err := compositions.RevertPatches()
+	if err != nil {
+		return err
+	}
+	if len(patches) == 0 {
+		return nil
+	}
+	s := migration.Step{
+		Name: revertCompositionsStep,
+		Type: migration.StepTypePatch,
+		Patch: &migration.PatchStep{
+			Type: migration.PatchTypeMerge,
+		},
+	}
+	target := migration.NewFileSystemTarget(migration.WithParentDirectory(planDir))
+	for _, p := range patches {
+		path := filepath.Join(revertCompositionsStep, fmt.Sprintf("%s.yaml", p.GetName()))
+		if err := target.Put(migration.UnstructuredWithMetadata{
+			Object: p,
+			Metadata: migration.Metadata{
+				Path: path,
+			},
+		}); err != nil {
+			return errors.Wrapf(err, "Failed to store the patch restoring the Composition: %s", p.GetName())
+		}
+		s.Patch.Files = append(s.Patch.Files, path)
+	}
+	migration.AddManualExecution(&s)
+	plan.Spec.Steps = append(plan.Spec.Steps, s)
+	return nil
+}
+
+func registerConfigurationPackageConverters(opts *Options, r *migration.Registry, nested []configuration.PackageMapping) error {
 	if err := r.AddCrossplanePackageTypes(); err != nil {
 		return errors.Wrap(err, "Failed to register the Provider package types with the migration registry")
 	}
 	cp := configuration.NewCompositionPreProcessor()
+	// the provider names are collected per Configuration package so that
+	// each package depends only on the providers of its own Compositions
+	if err := cp.AddPackage(opts.Generate.Configuration.PackageRoot); err != nil {
+		return errors.Wrap(err, "Failed to register the Configuration package")
+	}
+	for _, m := range nested {
+		if m.PackageRoot == "" {
+			continue
+		}
+		if err := cp.AddPackage(m.PackageRoot); err != nil {
+			return errors.Wrapf(err, "Failed to register the nested Configuration package %s", m.Source)
+		}
+	}
 	r.RegisterPreProcessor(migration.CategoryComposition, migration.PreProcessor(cp.GetSSOPNameFromComposition))
 	r.RegisterConfigurationMetadataConverter(migration.AllConfigurations, &configuration.ConfigMetaParameters{
-		FamilyVersion:        opts.Generate.AWSFamilyVersion,
-		Monolith:             "provider-aws",
-		CompositionProcessor: cp,
+		FamilyVersion:         opts.Generate.AWSFamilyVersion,
+		Monolith:              "provider-aws",
+		CompositionProcessor:  cp,
+		ConfigurationMappings: nested,
 	})
 	r.RegisterConfigurationMetadataConverter(migration.AllConfigurations, &configuration.ConfigMetaParameters{
-		FamilyVersion:        opts.Generate.AzureFamilyVersion,
-		Monolith:             "provider-azure",
-		CompositionProcessor: cp,
+		FamilyVersion:         opts.Generate.AzureFamilyVersion,
+		Monolith:              "provider-azure",
+		CompositionProcessor:  cp,
+		ConfigurationMappings: nested,
 	})
 	r.RegisterConfigurationMetadataConverter(migration.AllConfigurations, &configuration.ConfigMetaParameters{
-		FamilyVersion:        opts.Generate.GCPFamilyVersion,
-		Monolith:             "provider-gcp",
-		CompositionProcessor: cp,
+		FamilyVersion:         opts.Generate.GCPFamilyVersion,
+		Monolith:              "provider-gcp",
+		CompositionProcessor:  cp,
+		ConfigurationMappings: nested,
 	})
 	r.RegisterConfigurationPackageConverter(regexp.MustCompile(opts.Generate.Configuration.SourceConfigurationPackage), &configuration.ConfigPkgParameters{
 		PackageURL: opts.Generate.Configuration.TargetConfigurationPackage,
 	})
+	for _, m := range nested {
+		r.RegisterConfigurationPackageConverter(regexp.MustCompile(regexp.QuoteMeta(m.Source)), &configuration.ConfigPkgParameters{
+			PackageURL: m.Target,
+		})
+		r.RegisterPackageLockConverter(migration.CrossplaneLockName, &configuration.LockParameters{
+			PackageURL: m.Source,
+		})
+	}
 	// TODO: should we also handle missing registry (xpkg.upbound.io),
 	// i.e., is it the default?
 	// register converters for the family config packages
@@ -466,9 +753,27 @@ func registerConfigurationPackageConverters(opts *Options, r *migration.Registry
 		Monolith:             "provider-gcp",
 		CompositionProcessor: cp,
 	})
-	r.RegisterPackageLockConverter(migration.CrossplaneLockName, &configuration.LockParameters{
-		PackageURL: opts.Generate.Configuration.SourceConfigurationPackage,
-	})
+	// the lock entries of a monolith are only pruned if its family is
+	// being migrated.
+	for _, f := range []struct {
+		monolith      string
+		familyVersion string
+	}{
+		{monolith: "provider-aws", familyVersion: opts.Generate.AWSFamilyVersion},
+		{monolith: "provider-azure", familyVersion: opts.Generate.AzureFamilyVersion},
+		{monolith: "provider-gcp", familyVersion: opts.Generate.GCPFamilyVersion},
+	} {
+		lp := &configuration.LockParameters{
+			PackageURL: opts.Generate.Configuration.SourceConfigurationPackage,
+		}
+		if f.familyVersion != "" {
+			lp.FamilyVersion = f.familyVersion
+			lp.Monolith = f.monolith
+			lp.CompositionProcessor = cp
+			lp.ConfigurationMappings = nested
+		}
+		r.RegisterPackageLockConverter(migration.CrossplaneLockName, lp)
+	}
 	if err := r.AddCompositionTypes(); err != nil {
 		return errors.Wrap(err, "Failed to register the Crossplane Composition types with the migration registry")
 	}
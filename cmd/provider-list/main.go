@@ -90,13 +90,7 @@ func main() {
 
 	pg := migration.NewPlanGenerator(r, source, nil, migration.WithSkipGVKs(schema.GroupVersionKind{}))
 	kongCtx.FatalIfErrorf(pg.GeneratePlan(), "Failed to list the required provider family packages")
-	providers := make([]string, 0, len(mp.ProviderNames)+len(cp.ProviderNames))
-	for p := range mp.ProviderNames {
-		providers = append(providers, p)
-	}
-	for p := range cp.ProviderNames {
-		providers = append(providers, p)
-	}
+	providers := append(mp.SortedProviderNames(), cp.SortedProviderNames()...)
 	sort.Strings(providers)
 	logger := log.New(os.Stdout, "", 0)
 	for _, p := range providers {
@@ -15,6 +15,9 @@
 package common
 
 import (
+	"fmt"
+	"sync"
+
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
@@ -34,6 +37,28 @@ func AddToScheme(r *migration.Registry, sourceF, targetF func(s *runtime.Scheme)
 	return nil
 }
 
+var (
+	warningMu sync.Mutex
+	warnings  []string
+)
+
+// Warnf records a migration warning for a source configuration that
+// cannot be converted faithfully. The recorded warnings are retrieved with
+// Warnings so that they can be stored together with the migration plan.
+func Warnf(format string, args ...any) {
+	warningMu.Lock()
+	defer warningMu.Unlock()
+	warnings = append(warnings, fmt.Sprintf(format, args...))
+}
+
+// Warnings returns the migration warnings recorded so far in the order
+// they have been reported.
+func Warnings() []string {
+	warningMu.Lock()
+	defer warningMu.Unlock()
+	return append([]string(nil), warnings...)
+}
+
 // PtrFromString returns the parameter of the type string in the pointer type.
 func PtrFromString(s string) *string {
 	return &s
@@ -0,0 +1,287 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acm
+
+import (
+	"fmt"
+	"strings"
+
+	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/acm/v1beta1"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/acm/v1beta1"
+	route53v1beta1 "github.com/upbound/provider-aws/apis/route53/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+	"github.com/upbound/extensions-migration/converters/provider-aws/route53"
+)
+
+const (
+	// LabelCertificateID is the label of the converted Certificates whose
+	// value is the ID of the certificate, i.e., the last segment of its
+	// ARN. The converted resources referring to the certificates select
+	// them with this label.
+	LabelCertificateID = "acm.aws.upbound.io/certificate-id"
+
+	validationMethodDNS = "DNS"
+	validationRecordTTL = 60
+)
+
+var (
+	// ValidationZoneID is the ID of the Route53 hosted zone in which the
+	// DNS validation records of the converted Certificates are created. If
+	// it's set, a CertificateValidation and the validation Records are
+	// generated for each Certificate validated with DNS.
+	ValidationZoneID string
+
+	// CertificateReferences configures whether the converted resources
+	// referring to ACM certificates with their ARNs select the converted
+	// Certificates instead. The selectors only resolve for the certificates
+	// converted by the migration, so it must only be enabled if all the
+	// referred certificates are migrated.
+	CertificateReferences = false
+)
+
+// certificateFieldNames describes the conversion of the community
+// Certificate fields into the official Certificate fields.
+var certificateFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"certificateAuthorityARN":         "certificateAuthorityArn",
+		"certificateAuthorityARNRef":      "certificateAuthorityArnRef",
+		"certificateAuthorityARNSelector": "certificateAuthorityArnSelector",
+		"domainValidationOptions":         "validationOption",
+	},
+	Paths: map[string]string{
+		"certificateTransparencyLoggingPreference": "options[0].certificateTransparencyLoggingPreference",
+	},
+	Transform: convertCertificateOptions,
+}
+
+// convertCertificateOptions moves the certificate transparency logging
+// preference of the community Certificate into the options block of the
+// official Certificate.
+func convertCertificateOptions(forProvider map[string]any) {
+	if p, ok := forProvider["certificateTransparencyLoggingPreference"]; ok {
+		delete(forProvider, "certificateTransparencyLoggingPreference")
+		forProvider["options"] = []any{map[string]any{
+			"certificateTransparencyLoggingPreference": p,
+		}}
+	}
+}
+
+// CertificateID returns the ID of the certificate with the given ARN, or
+// false if the ARN is not an ACM certificate ARN.
+func CertificateID(arn string) (string, bool) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "acm" || !strings.HasPrefix(parts[5], "certificate/") {
+		return "", false
+	}
+	id := strings.TrimPrefix(parts[5], "certificate/")
+	return id, id != ""
+}
+
+// CertificateSelector returns the selector of the converted Certificate
+// with the given ARN, or nil if the references to the certificates are
+// disabled or the ARN is not an ACM certificate ARN.
+func CertificateSelector(arn *string) *xpv1.Selector {
+	if !CertificateReferences || arn == nil {
+		return nil
+	}
+	id, ok := CertificateID(*arn)
+	if !ok {
+		return nil
+	}
+	return &xpv1.Selector{
+		MatchLabels: map[string]string{
+			LabelCertificateID: id,
+		},
+	}
+}
+
+func CertificateResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1beta1.Certificate)
+	target := &targetv1beta1.Certificate{}
+	if err := certificateFieldNames.CopyInto(source, target, targetv1beta1.Certificate_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	externalName := meta.GetExternalName(source)
+	if id, ok := CertificateID(externalName); ok {
+		meta.AddLabels(target, map[string]string{LabelCertificateID: id})
+	}
+	certificateMRs := []resource.Managed{target}
+	if ValidationZoneID == "" || source.Spec.ForProvider.ValidationMethod == nil || *source.Spec.ForProvider.ValidationMethod != validationMethodDNS {
+		return certificateMRs, nil
+	}
+	records, err := validationRecords(source)
+	if err != nil {
+		return nil, err
+	}
+	fqdns := make([]any, 0, len(records))
+	for i, r := range records {
+		record, err := newValidationRecord(source, i, r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the validation record at index %d", i)
+		}
+		certificateMRs = append(certificateMRs, record)
+		fqdns = append(fqdns, r.name)
+	}
+	validation, err := newCertificateValidation(source, fqdns)
+	if err != nil {
+		return nil, err
+	}
+	return append(certificateMRs, validation), nil
+}
+
+type validationRecord struct {
+	name, recordType, value string
+}
+
+// validationRecords returns the DNS validation records of the given
+// community Certificate observed in its status. The records of the
+// Certificates that have not been observed, e.g., the bases of composed
+// Certificates, are not known.
+func validationRecords(source *srcv1beta1.Certificate) ([]validationRecord, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(source)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert the source Certificate to unstructured")
+	}
+	v, err := fieldpath.Pave(u).GetValue("status.atProvider.domainValidationOptions")
+	if err != nil {
+		if fieldpath.IsNotFound(err) {
+			common.Warnf("Certificate %q: no DNS validation records are observed, the validation Records are not generated", source.GetName())
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get the domain validation options of the source Certificate")
+	}
+	l, _ := v.([]any)
+	records := make([]validationRecord, 0, len(l))
+	seen := make(map[string]bool, len(l))
+	for _, o := range l {
+		om, ok := o.(map[string]any)
+		if !ok {
+			continue
+		}
+		rr, ok := om["resourceRecord"].(map[string]any)
+		if !ok {
+			continue
+		}
+		r := validationRecord{}
+		r.name, _ = rr["name"].(string)
+		r.recordType, _ = rr["type"].(string)
+		r.value, _ = rr["value"].(string)
+		// the wildcard and the apex domains share the same record
+		if r.name == "" || seen[r.name] {
+			continue
+		}
+		seen[r.name] = true
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// newValidationRecord returns the official Route53 Record validating the
+// given community Certificate.
+func newValidationRecord(source *srcv1beta1.Certificate, i int, r validationRecord) (*route53v1beta1.Record, error) {
+	record := &route53v1beta1.Record{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]any{
+		"spec": map[string]any{
+			"forProvider": map[string]any{
+				"region":         route53.RegionFor(source),
+				"zoneId":         ValidationZoneID,
+				"name":           r.name,
+				"type":           r.recordType,
+				"records":        []any{r.value},
+				"ttl":            float64(validationRecordTTL),
+				"allowOverwrite": true,
+			},
+		},
+	}, record); err != nil {
+		return nil, errors.Wrap(err, "failed to convert into Record")
+	}
+	record.SetGroupVersionKind(route53v1beta1.Record_GroupVersionKind)
+	if len(source.GetName()) > 0 {
+		record.SetName(fmt.Sprintf("%s-validation-%d", source.GetName(), i))
+	}
+	setChildMetadata(source, record, route53v1beta1.Record_Kind)
+	// the external name of a Record is <zone ID>_<name>_<type>
+	meta.SetExternalName(record, fmt.Sprintf("%s_%s_%s", ValidationZoneID, strings.TrimSuffix(r.name, "."), r.recordType))
+	return record, nil
+}
+
+// newCertificateValidation returns the official CertificateValidation of
+// the given community Certificate. It selects the converted Certificate.
+func newCertificateValidation(source *srcv1beta1.Certificate, fqdns []any) (*targetv1beta1.CertificateValidation, error) {
+	forProvider := map[string]any{
+		"region": source.Spec.ForProvider.Region,
+		"certificateArnRef": map[string]any{
+			"name": source.GetName(),
+		},
+		"certificateArnSelector": map[string]any{
+			"matchControllerRef": true,
+		},
+	}
+	if externalName := meta.GetExternalName(source); len(externalName) > 0 {
+		forProvider["certificateArn"] = externalName
+	}
+	if len(fqdns) > 0 {
+		forProvider["validationRecordFqdns"] = fqdns
+	}
+	validation := &targetv1beta1.CertificateValidation{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]any{
+		"spec": map[string]any{
+			"forProvider": forProvider,
+		},
+	}, validation); err != nil {
+		return nil, errors.Wrap(err, "failed to convert into CertificateValidation")
+	}
+	validation.SetGroupVersionKind(targetv1beta1.CertificateValidation_GroupVersionKind)
+	if len(source.GetName()) > 0 {
+		validation.SetName(fmt.Sprintf("%s-validation", source.GetName()))
+	}
+	setChildMetadata(source, validation, targetv1beta1.CertificateValidation_Kind)
+	return validation, nil
+}
+
+// setChildMetadata sets the labels, the deletion policy and the
+// ProviderConfig of the given resource generated for the given community
+// Certificate.
+func setChildMetadata(source *srcv1beta1.Certificate, mg resource.Managed, kind string) {
+	if len(source.Labels) > 0 {
+		labels := make(map[string]string, len(source.Labels)+1)
+		for k, v := range source.Labels {
+			labels[k] = v
+		}
+		labels["resourceType"] = kind
+		mg.SetLabels(labels)
+	}
+	mg.SetDeletionPolicy(source.Spec.DeletionPolicy)
+	mg.SetProviderConfigReference(source.Spec.ProviderConfigReference)
+}
+
+// CertificateComposition converts the patches of a composed community
+// Certificate and routes them to the converted Certificate.
+func CertificateComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	patchesToAdd, err := certificateFieldNames.ConvertPatches(sourceTemplate)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the Certificate patches")
+	}
+	return common.SplittedResourcePatches(convertedTemplates, targetv1beta1.Certificate_Kind, patchesToAdd)
+}
@@ -20,6 +20,8 @@ import (
 	"github.com/crossplane/upjet/pkg/migration"
 	"github.com/pkg/errors"
 	targetv1beta1 "github.com/upbound/provider-aws/apis/apigatewayv2/v1beta1"
+
+	"github.com/upbound/extensions-migration/converters/provider-aws/acm"
 )
 
 func DomainNameResource(mg resource.Managed) ([]resource.Managed, error) {
@@ -40,7 +42,7 @@ func DomainNameResource(mg resource.Managed) ([]resource.Managed, error) {
 			targetDnc := targetv1beta1.DomainNameConfigurationParameters{
 				CertificateArn:                      sourceDnc.CertificateARN,
 				CertificateArnRef:                   nil,
-				CertificateArnSelector:              nil,
+				CertificateArnSelector:              acm.CertificateSelector(sourceDnc.CertificateARN),
 				EndpointType:                        sourceDnc.EndpointType,
 				OwnershipVerificationCertificateArn: sourceDnc.OwnershipVerificationCertificateARN,
 				SecurityPolicy:                      sourceDnc.SecurityPolicy,
@@ -52,9 +54,11 @@ func DomainNameResource(mg resource.Managed) ([]resource.Managed, error) {
 			// sourceDnc.DomainNameStatus
 			// sourceDnc.DomainNameStatusMessage
 
-			// no-op: ref types introduced at target
-			// targetDnc.CertificateArnRef
-			// targetDnc.CertificateArnSelector
+			// the converted Certificate is selected instead of its ARN as
+			// the selector is not resolved while the ARN is set
+			if targetDnc.CertificateArnSelector != nil {
+				targetDnc.CertificateArn = nil
+			}
 
 			target.Spec.ForProvider.DomainNameConfiguration[i] = targetDnc
 		}
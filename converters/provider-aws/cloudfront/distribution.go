@@ -15,68 +15,165 @@
 package cloudfront
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/cloudfront/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/upjet/pkg/migration"
 	"github.com/pkg/errors"
 	targetv1beta1 "github.com/upbound/provider-aws/apis/cloudfront/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
 )
 
+const (
+	fieldDistributionConfig = "spec.forProvider.distributionConfig"
+	fieldItems              = "items"
+)
+
+// distributionLists are the community DistributionConfig objects wrapping
+// a list in their items field, and the official fields the lists are
+// converted into.
+var distributionLists = map[string]string{
+	"aliases":                    "aliases",
+	"allowedMethods":             "allowedMethods",
+	"cacheBehaviors":             "orderedCacheBehavior",
+	"cachedMethods":              "cachedMethods",
+	"customErrorResponses":       "customErrorResponse",
+	"customHeaders":              "customHeader",
+	"functionAssociations":       "functionAssociation",
+	"headers":                    "headers",
+	"lambdaFunctionAssociations": "lambdaFunctionAssociation",
+	"members":                    "member",
+	"originGroups":               "originGroup",
+	"originSSLProtocols":         "originSslProtocols",
+	"origins":                    "origin",
+	"queryStringCacheKeys":       "queryStringCacheKeys",
+	"statusCodes":                "statusCodes",
+	"trustedKeyGroups":           "trustedKeyGroups",
+	"trustedSigners":             "trustedSigners",
+	"whitelistedNames":           "whitelistedNames",
+}
+
+// distributionBlocks are the community DistributionConfig objects that are
+// blocks, i.e., single element lists, in the official Distribution.
+var distributionBlocks = map[string]bool{
+	"cookies":              true,
+	"customOriginConfig":   true,
+	"defaultCacheBehavior": true,
+	"failoverCriteria":     true,
+	"forwardedValues":      true,
+	"geoRestriction":       true,
+	"logging":              true,
+	"originShield":         true,
+	"restrictions":         true,
+	"s3OriginConfig":       true,
+	"viewerCertificate":    true,
+}
+
+// distributionFieldNames maps the community DistributionConfig field names
+// that are not only different in the casing of their acronyms to the
+// official field names.
+var distributionFieldNames = map[string]string{
+	"cloudFrontDefaultCertificate": "cloudfrontDefaultCertificate",
+	"headerName":                   "name",
+	"headerValue":                  "value",
+	"id":                           "originId",
+	"lambdaFunctionARN":            "lambdaArn",
+	"logging":                      "loggingConfig",
+}
+
+// distributionDropped are the community DistributionConfig fields that have
+// no official counterpart.
+var distributionDropped = map[string]bool{
+	"callerReference":   true,
+	"certificate":       true,
+	"certificateSource": true,
+	"quantity":          true,
+}
+
+var acronymReplacer = strings.NewReplacer("IPV6", "Ipv6", "ARN", "Arn", "ACL", "Acl", "TTL", "Ttl", "SSL", "Ssl", "ID", "Id")
+
+// distributionFieldName returns the official field name for the given
+// community DistributionConfig field name.
+func distributionFieldName(k string) string {
+	if n, ok := distributionFieldNames[k]; ok {
+		return n
+	}
+	return acronymReplacer.Replace(k)
+}
+
+// DistributionResource converts a community Distribution into an official
+// Distribution. The fields of the community DistributionConfig are moved
+// into the spec.forProvider of the official Distribution, the objects
+// wrapping lists are unwrapped and the nested objects are converted into
+// blocks.
 func DistributionResource(mg resource.Managed) ([]resource.Managed, error) {
 	source := mg.(*srcv1alpha1.Distribution)
 	target := &targetv1beta1.Distribution{}
-	if _, err := migration.CopyInto(source, target, targetv1beta1.Distribution_GroupVersionKind, "spec.forProvider.tags"); err != nil {
+	if _, err := migration.CopyInto(source, target, targetv1beta1.Distribution_GroupVersionKind, "spec.forProvider.tags", "spec.forProvider.distributionConfig"); err != nil {
 		return nil, errors.Wrap(err, "failed to copy source into target")
 	}
 
-	target.Spec.ForProvider.Comment = source.Spec.ForProvider.DistributionConfig.Comment
-
-	target.Spec.ForProvider.DefaultCacheBehavior = make([]targetv1beta1.DefaultCacheBehaviorParameters, 1)
-	target.Spec.ForProvider.DefaultCacheBehavior[0].AllowedMethods = source.Spec.ForProvider.DistributionConfig.DefaultCacheBehavior.AllowedMethods.Items
-	target.Spec.ForProvider.DefaultCacheBehavior[0].CachedMethods = source.Spec.ForProvider.DistributionConfig.DefaultCacheBehavior.AllowedMethods.CachedMethods.Items
-	target.Spec.ForProvider.DefaultCacheBehavior[0].Compress = source.Spec.ForProvider.DistributionConfig.DefaultCacheBehavior.Compress
-	target.Spec.ForProvider.DefaultCacheBehavior[0].TargetOriginID = source.Spec.ForProvider.DistributionConfig.DefaultCacheBehavior.TargetOriginID
-	target.Spec.ForProvider.DefaultCacheBehavior[0].ViewerProtocolPolicy = source.Spec.ForProvider.DistributionConfig.DefaultCacheBehavior.ViewerProtocolPolicy
-	target.Spec.ForProvider.DefaultCacheBehavior[0].CachePolicyID = source.Spec.ForProvider.DistributionConfig.DefaultCacheBehavior.CachePolicyID
-	target.Spec.ForProvider.DefaultCacheBehavior[0].OriginRequestPolicyID = source.Spec.ForProvider.DistributionConfig.DefaultCacheBehavior.OriginRequestPolicyID
-	target.Spec.ForProvider.DefaultCacheBehavior[0].ViewerProtocolPolicy = source.Spec.ForProvider.DistributionConfig.DefaultCacheBehavior.ViewerProtocolPolicy
-
-	target.Spec.ForProvider.Enabled = source.Spec.ForProvider.DistributionConfig.Enabled
-
-	target.Spec.ForProvider.ViewerCertificate = make([]targetv1beta1.ViewerCertificateParameters, 1)
-	target.Spec.ForProvider.ViewerCertificate[0].AcmCertificateArn = source.Spec.ForProvider.DistributionConfig.ViewerCertificate.ACMCertificateARN
-	target.Spec.ForProvider.ViewerCertificate[0].CloudfrontDefaultCertificate = source.Spec.ForProvider.DistributionConfig.ViewerCertificate.CloudFrontDefaultCertificate
-	target.Spec.ForProvider.ViewerCertificate[0].SSLSupportMethod = source.Spec.ForProvider.DistributionConfig.ViewerCertificate.SSLSupportMethod
-	target.Spec.ForProvider.ViewerCertificate[0].MinimumProtocolVersion = source.Spec.ForProvider.DistributionConfig.ViewerCertificate.MinimumProtocolVersion
-
-	target.Spec.ForProvider.WebACLID = source.Spec.ForProvider.DistributionConfig.WebACLID
-	if source.Spec.ForProvider.DistributionConfig.Origins != nil {
-		for _, i := range source.Spec.ForProvider.DistributionConfig.Origins.Items {
-			if i.S3OriginConfig != nil {
-				target.Spec.ForProvider.Origin = append(target.Spec.ForProvider.Origin, targetv1beta1.OriginParameters{
-					DomainName:            i.DomainName,
-					OriginAccessControlID: i.ID,
-					S3OriginConfig:        []targetv1beta1.S3OriginConfigParameters{{OriginAccessIdentity: i.S3OriginConfig.OriginAccessIdentity}},
-				})
-			} else {
-				convertHttpPort := float64(*i.CustomOriginConfig.HTTPPort)
-				convertHttpsPort := float64(*i.CustomOriginConfig.HTTPSPort)
-				convertOriginKeepaliveTimeout := float64(*i.CustomOriginConfig.OriginKeepaliveTimeout)
-				convertOriginReadTimeout := float64(*i.CustomOriginConfig.OriginReadTimeout)
-
-				target.Spec.ForProvider.Origin = append(target.Spec.ForProvider.Origin, targetv1beta1.OriginParameters{
-					DomainName: i.DomainName,
-					OriginID:   i.ID,
-					CustomOriginConfig: []targetv1beta1.CustomOriginConfigParameters{{
-						HTTPPort:               &convertHttpPort,
-						HTTPSPort:              &convertHttpsPort,
-						OriginKeepaliveTimeout: &convertOriginKeepaliveTimeout,
-						OriginReadTimeout:      &convertOriginReadTimeout,
-						OriginProtocolPolicy:   i.CustomOriginConfig.OriginProtocolPolicy,
-						OriginSSLProtocols:     i.CustomOriginConfig.OriginSSLProtocols.Items,
-					}},
-				})
+	pv := fieldpath.Pave(migration.ToSanitizedUnstructured(source).Object)
+	dc, err := pv.GetValue(fieldDistributionConfig)
+	if err != nil && !fieldpath.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to get the distribution configuration")
+	}
+	forProvider := map[string]any{}
+	if m, ok := dc.(map[string]any); ok {
+		forProvider = convertDistributionObject(m)
+	}
+	// restrictions and viewerCertificate are required by the official
+	// Distribution
+	if _, ok := forProvider["restrictions"]; !ok {
+		forProvider["restrictions"] = []any{map[string]any{
+			"geoRestriction": []any{map[string]any{"restrictionType": "none"}},
+		}}
+	}
+	if _, ok := forProvider["viewerCertificate"]; !ok {
+		forProvider["viewerCertificate"] = []any{map[string]any{"cloudfrontDefaultCertificate": true}}
+	}
+	// the official viewerCertificate cannot refer to the converted ACM
+	// Certificates, so its acmCertificateArn keeps the ARN of the
+	// certificate, which is not changed by the migration
+	if vc, ok := forProvider["viewerCertificate"].([]any); ok && len(vc) > 0 {
+		if m, ok := vc[0].(map[string]any); ok && m["acmCertificateArn"] != nil {
+			common.Warnf("Distribution %q: references to the converted ACM Certificates are not supported, the viewer certificate keeps its ACM certificate ARN", source.GetName())
+		}
+	}
+	converted := &targetv1beta1.Distribution{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]any{
+		"spec": map[string]any{
+			"forProvider": forProvider,
+		},
+	}, converted); err != nil {
+		return nil, errors.Wrap(err, "failed to convert the distribution configuration")
+	}
+	region := target.Spec.ForProvider.Region
+	target.Spec.ForProvider = converted.Spec.ForProvider
+	target.Spec.ForProvider.Region = region
+
+	tags, err := pv.GetValue("spec.forProvider.tags")
+	if err != nil && !fieldpath.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to get the tags")
+	}
+	if l, ok := tags.([]any); ok {
+		target.Spec.ForProvider.Tags = make(map[string]*string, len(l))
+		for _, t := range l {
+			tm, ok := t.(map[string]any)
+			if !ok {
+				continue
 			}
+			k, _ := tm["key"].(string)
+			v, _ := tm["value"].(string)
+			target.Spec.ForProvider.Tags[k] = common.PtrFromString(v)
 		}
 	}
 
@@ -84,3 +181,203 @@ func DistributionResource(mg resource.Managed) ([]resource.Managed, error) {
 		target,
 	}, nil
 }
+
+// convertDistributionObject converts a community DistributionConfig object
+// into its official representation.
+func convertDistributionObject(m map[string]any) map[string]any {
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		if v == nil || distributionDropped[k] {
+			continue
+		}
+		switch {
+		case k == "allowedMethods":
+			am, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			if l, ok := am[fieldItems]; ok {
+				result["allowedMethods"] = convertDistributionValue(l)
+			}
+			if cm, ok := am["cachedMethods"].(map[string]any); ok {
+				if l, ok := cm[fieldItems]; ok {
+					result["cachedMethods"] = convertDistributionValue(l)
+				}
+			}
+		case k == "geoRestriction":
+			gm, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			g := map[string]any{
+				"restrictionType": gm["restrictionType"],
+			}
+			if l, ok := gm[fieldItems]; ok {
+				g["locations"] = convertDistributionValue(l)
+			}
+			result[k] = []any{g}
+		case k == "logging":
+			lm, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			// disabled logging configurations are not converted
+			if enabled, ok := lm["enabled"].(bool); ok && !enabled {
+				continue
+			}
+			result[distributionFieldName(k)] = []any{convertDistributionObject(lm)}
+		case k == "responseCode":
+			// the response codes of the custom error responses are
+			// strings in the community DistributionConfig
+			code, ok := v.(string)
+			if !ok {
+				result[k] = convertDistributionValue(v)
+				continue
+			}
+			f, err := strconv.ParseFloat(code, 64)
+			if err != nil {
+				common.Warnf("Distribution custom error response code %q is not a number and is dropped", code)
+				continue
+			}
+			result[k] = f
+		case distributionLists[k] != "":
+			lm, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			if l, ok := lm[fieldItems]; ok {
+				result[distributionLists[k]] = convertDistributionValue(l)
+			}
+		case distributionBlocks[k]:
+			om, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			result[distributionFieldName(k)] = []any{convertDistributionObject(om)}
+		default:
+			result[distributionFieldName(k)] = convertDistributionValue(v)
+		}
+	}
+	return result
+}
+
+func convertDistributionValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		return convertDistributionObject(t)
+	case []any:
+		l := make([]any, 0, len(t))
+		for _, e := range t {
+			l = append(l, convertDistributionValue(e))
+		}
+		return l
+	case int64:
+		return float64(t)
+	case int32:
+		return float64(t)
+	case int:
+		return float64(t)
+	default:
+		return v
+	}
+}
+
+// DistributionComposition converts the patches of a composed community
+// Distribution targeting the fields of its DistributionConfig.
+func DistributionComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	patchesToAdd, err := providerawscommon.ConvertComposedTemplateTags(sourceTemplate)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert tags")
+	}
+	for _, p := range sourceTemplate.Patches {
+		switch p.Type { //nolint:exhaustive
+		case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite, "":
+			if p.ToFieldPath == nil {
+				continue
+			}
+			to, ok := convertDistributionFieldPath(*p.ToFieldPath)
+			if !ok {
+				continue
+			}
+			p.ToFieldPath = &to
+		case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeCombineToComposite:
+			if p.FromFieldPath == nil {
+				continue
+			}
+			from, ok := convertDistributionFieldPath(*p.FromFieldPath)
+			if !ok {
+				continue
+			}
+			p.FromFieldPath = &from
+		default:
+			continue
+		}
+		patchesToAdd = append(patchesToAdd, p)
+	}
+	for i := range convertedTemplates {
+		convertedTemplates[i].Patches = append(convertedTemplates[i].Patches, patchesToAdd...)
+	}
+	return nil
+}
+
+// convertDistributionFieldPath converts a field path into the community
+// DistributionConfig into the corresponding official Distribution field
+// path. Returns false if the field path does not target the
+// DistributionConfig or cannot be converted.
+func convertDistributionFieldPath(path string) (string, bool) {
+	prefix := fieldDistributionConfig + "."
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+	segments := strings.Split(strings.TrimPrefix(path, prefix), ".")
+	converted := make([]string, 0, len(segments))
+	for i := 0; i < len(segments); i++ {
+		name, index := splitIndex(segments[i])
+		var next, nextIndex string
+		if i+1 < len(segments) {
+			next, nextIndex = splitIndex(segments[i+1])
+		}
+		switch {
+		case name == "allowedMethods" && next == "cachedMethods":
+			if i+2 >= len(segments) {
+				return "", false
+			}
+			items, itemsIndex := splitIndex(segments[i+2])
+			if items != fieldItems {
+				return "", false
+			}
+			converted = append(converted, "cachedMethods"+itemsIndex)
+			i += 2
+		case distributionLists[name] != "":
+			// the objects wrapping lists can only be patched through
+			// their items
+			if next != fieldItems {
+				return "", false
+			}
+			converted = append(converted, distributionLists[name]+nextIndex)
+			i++
+		case name == "geoRestriction" && next == fieldItems:
+			converted = append(converted, "geoRestriction[0]", "locations"+nextIndex)
+			i++
+		case distributionBlocks[name]:
+			if index == "" {
+				index = "[0]"
+			}
+			converted = append(converted, distributionFieldName(name)+index)
+		case distributionDropped[name]:
+			return "", false
+		default:
+			converted = append(converted, distributionFieldName(name)+index)
+		}
+	}
+	return fmt.Sprintf("spec.forProvider.%s", strings.Join(converted, ".")), true
+}
+
+// splitIndex splits a field path segment into the field name and its
+// index, if any.
+func splitIndex(segment string) (string, string) {
+	if i := strings.Index(segment, "["); i >= 0 {
+		return segment[:i], segment[i:]
+	}
+	return segment, ""
+}
@@ -0,0 +1,106 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudwatch
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/cloudwatch/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/cloudwatch/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// MetricAlarmFieldNames describes the conversion of the community
+// MetricAlarm fields into the official MetricAlarm fields.
+var MetricAlarmFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"evaluateLowSampleCountPercentile": "evaluateLowSampleCountPercentiles",
+		"metrics":                          "metricQuery",
+		"oKActions":                        "okActions",
+		"thresholdMetricID":                "thresholdMetricId",
+	},
+	Items: map[string]map[string]string{
+		"metricQuery": {
+			"accountID": "accountId",
+		},
+	},
+	Transform: convertMetricAlarmDimensions,
+}
+
+// convertMetricAlarmDimensions converts the name/value dimension lists of
+// the community MetricAlarm and of its metric queries into maps, and the
+// metric statistics of the queries into metric blocks.
+func convertMetricAlarmDimensions(forProvider map[string]any) {
+	dimensionsToMap(forProvider)
+	l, ok := forProvider["metricQuery"].([]any)
+	if !ok {
+		return
+	}
+	for _, q := range l {
+		qm, ok := q.(map[string]any)
+		if !ok {
+			continue
+		}
+		ms, ok := qm["metricStat"].(map[string]any)
+		if !ok {
+			continue
+		}
+		delete(qm, "metricStat")
+		metric := map[string]any{}
+		if m, ok := ms["metric"].(map[string]any); ok {
+			for k, v := range m {
+				metric[k] = v
+			}
+			dimensionsToMap(metric)
+		}
+		for _, k := range []string{"period", "stat", "unit"} {
+			if v, ok := ms[k]; ok {
+				metric[k] = v
+			}
+		}
+		qm["metric"] = []any{metric}
+	}
+}
+
+// dimensionsToMap converts the name/value dimension list of the given
+// object into a map.
+func dimensionsToMap(m map[string]any) {
+	l, ok := m["dimensions"].([]any)
+	if !ok {
+		return
+	}
+	dimensions := make(map[string]any, len(l))
+	for _, d := range l {
+		dm, ok := d.(map[string]any)
+		if !ok {
+			continue
+		}
+		if k, ok := dm["name"].(string); ok {
+			dimensions[k] = dm["value"]
+		}
+	}
+	m["dimensions"] = dimensions
+}
+
+func MetricAlarmResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.MetricAlarm)
+	target := &targetv1beta1.MetricAlarm{}
+	if err := MetricAlarmFieldNames.CopyInto(source, target, targetv1beta1.MetricAlarm_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
@@ -17,16 +17,27 @@ package cloudwatchlogs
 import (
 	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/cloudwatchlogs/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
-	"github.com/crossplane/upjet/pkg/migration"
-	"github.com/pkg/errors"
 	targetv1beta1 "github.com/upbound/provider-aws/apis/cloudwatchlogs/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
 )
 
+// LogGroupFieldNames describes the conversion of the community LogGroup
+// fields into the official Group fields.
+var LogGroupFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"kmsKeyID":         "kmsKeyId",
+		"kmsKeyIDRef":      "kmsKeyIdRef",
+		"kmsKeyIDSelector": "kmsKeyIdSelector",
+	},
+	TagsMap: true,
+}
+
 func LogGroupResource(mg resource.Managed) ([]resource.Managed, error) {
 	source := mg.(*srcv1alpha1.LogGroup)
 	target := &targetv1beta1.Group{}
-	if _, err := migration.CopyInto(source, target, targetv1beta1.Group_GroupVersionKind); err != nil {
-		return nil, errors.Wrap(err, "failed to copy source into target")
+	if err := LogGroupFieldNames.CopyInto(source, target, targetv1beta1.Group_GroupVersionKind); err != nil {
+		return nil, err
 	}
 	return []resource.Managed{
 		target,
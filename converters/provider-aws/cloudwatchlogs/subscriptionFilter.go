@@ -0,0 +1,61 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudwatchlogs
+
+import (
+	"fmt"
+
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/cloudwatchlogs/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/cloudwatchlogs/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// SubscriptionFilterFieldNames describes the conversion of the community
+// SubscriptionFilter fields into the official SubscriptionFilter fields.
+var SubscriptionFilterFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"destinationARN":         "destinationArn",
+		"destinationARNRef":      "destinationArnRef",
+		"destinationARNSelector": "destinationArnSelector",
+		"filterName":             "name",
+		"roleARN":                "roleArn",
+		"roleARNRef":             "roleArnRef",
+		"roleARNSelector":        "roleArnSelector",
+	},
+}
+
+// SubscriptionFilterResource converts a community SubscriptionFilter into
+// an official SubscriptionFilter. The official resource is imported with
+// the <log group name>|<filter name> identifier.
+func SubscriptionFilterResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.SubscriptionFilter)
+	target := &targetv1beta1.SubscriptionFilter{}
+	if err := SubscriptionFilterFieldNames.CopyInto(source, target, targetv1beta1.SubscriptionFilter_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	name := meta.GetExternalName(source)
+	if target.Spec.ForProvider.Name == nil && len(name) > 0 {
+		target.Spec.ForProvider.Name = &name
+	}
+	if target.Spec.ForProvider.LogGroupName != nil && target.Spec.ForProvider.Name != nil {
+		meta.SetExternalName(target, fmt.Sprintf("%s|%s", *target.Spec.ForProvider.LogGroupName, *target.Spec.ForProvider.Name))
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
@@ -0,0 +1,304 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const fieldForProvider = "spec.forProvider"
+
+var (
+	listIndex     = regexp.MustCompile(`\[\d+]`)
+	wildcardIndex = regexp.MustCompile(`\[\*]`)
+)
+
+// FieldNames describes how the spec.forProvider fields of a community
+// resource are converted into the spec.forProvider fields of an official
+// resource.
+type FieldNames struct {
+	// Renames maps the renamed community fields to the official fields.
+	Renames map[string]string
+	// Blocks are the official names of the community objects that are
+	// blocks, i.e., single element lists, in the official resource.
+	Blocks map[string]bool
+	// Items maps the official names of the list fields to the renames
+	// of the fields of their items.
+	Items map[string]map[string]string
+	// Inline maps the community objects whose fields are moved to
+	// spec.forProvider in the official resource to the renames of their
+	// fields. The fields without renames keep their names.
+	Inline map[string]map[string]string
+	// Paths maps the prefixes of the community field paths relative to
+	// spec.forProvider to the official ones. They are used to convert the
+	// patches of nested fields not covered by the other conversions. The
+	// longest matching prefix wins, and a [*] in a prefix matches any list
+	// index, which is kept at the [*] of the official prefix.
+	Paths map[string]string
+	// Transform, if set, is called with the converted spec.forProvider of
+	// the resource for further conversions, e.g., of nested blocks.
+	Transform func(forProvider map[string]any)
+	// TagsMap is set if the tags of the community resource are already a
+	// map, in which case the tags patches are kept as they are.
+	TagsMap bool
+}
+
+// renamedUnstructured returns the sanitized unstructured representation of
+// the given community resource with its spec.forProvider fields renamed to
+// the official ones, its nested objects converted into blocks and its
+// key/value tags list converted into a map.
+func (f FieldNames) renamedUnstructured(source resource.Managed) (unstructured.Unstructured, error) {
+	u := migration.ToSanitizedUnstructured(source)
+	forProvider, err := fieldpath.Pave(u.Object).GetValue(fieldForProvider)
+	if err != nil {
+		if fieldpath.IsNotFound(err) {
+			return u, nil
+		}
+		return u, errors.Wrap(err, "failed to get the parameters of the source resource")
+	}
+	m, ok := forProvider.(map[string]any)
+	if !ok {
+		return u, nil
+	}
+	for o, renames := range f.Inline {
+		inlined, ok := m[o].(map[string]any)
+		if !ok {
+			continue
+		}
+		delete(m, o)
+		for k, v := range inlined {
+			if t, ok := renames[k]; ok {
+				k = t
+			}
+			m[k] = v
+		}
+	}
+	for s, t := range f.Renames {
+		if v, ok := m[s]; ok {
+			delete(m, s)
+			m[t] = v
+		}
+	}
+	for b := range f.Blocks {
+		if o, ok := m[b].(map[string]any); ok {
+			m[b] = []any{o}
+		}
+	}
+	for l, renames := range f.Items {
+		items, ok := m[l].([]any)
+		if !ok {
+			continue
+		}
+		for _, i := range items {
+			im, ok := i.(map[string]any)
+			if !ok {
+				continue
+			}
+			for s, t := range renames {
+				if v, ok := im[s]; ok {
+					delete(im, s)
+					im[t] = v
+				}
+			}
+		}
+	}
+	if l, ok := m["tags"].([]any); ok {
+		tags := make(map[string]any, len(l))
+		for _, t := range l {
+			tm, ok := t.(map[string]any)
+			if !ok {
+				continue
+			}
+			if k, ok := tm["key"].(string); ok {
+				tags[k] = tm["value"]
+			}
+		}
+		m["tags"] = tags
+	}
+	if f.Transform != nil {
+		f.Transform(m)
+	}
+	return u, nil
+}
+
+// CopyInto copies the given community resource into the given official
+// resource after renaming its fields.
+func (f FieldNames) CopyInto(source, target resource.Managed, gvk schema.GroupVersionKind, skipFieldPaths ...string) error {
+	u, err := f.renamedUnstructured(source)
+	if err != nil {
+		return err
+	}
+	_, err = migration.CopyInto(&u, target, gvk, skipFieldPaths...)
+	return errors.Wrap(err, "failed to copy source into target")
+}
+
+// RenameFieldPath renames the given community field path if it targets a
+// renamed field, a field of a block or a field of an inlined object.
+func (f FieldNames) RenameFieldPath(path *string) (string, bool) {
+	if path == nil || !strings.HasPrefix(*path, fieldForProvider+".") {
+		return "", false
+	}
+	rest := strings.TrimPrefix(*path, fieldForProvider+".")
+	if t, ok := f.convertPathPrefix(rest); ok {
+		return fmt.Sprintf("%s.%s", fieldForProvider, t), true
+	}
+	name := rest
+	if i := strings.IndexAny(rest, ".["); i >= 0 {
+		name = rest[:i]
+	}
+	if renames, ok := f.Inline[name]; ok {
+		field := strings.TrimPrefix(rest, name+".")
+		if field == rest {
+			return "", false
+		}
+		fieldName := field
+		if i := strings.IndexAny(field, ".["); i >= 0 {
+			fieldName = field[:i]
+		}
+		if t, ok := renames[fieldName]; ok {
+			field = t + strings.TrimPrefix(field, fieldName)
+		}
+		return fmt.Sprintf("%s.%s", fieldForProvider, field), true
+	}
+	t, renamed := f.Renames[name]
+	if !renamed {
+		t = name
+	}
+	suffix := strings.TrimPrefix(rest, name)
+	block := f.Blocks[t] && !strings.HasPrefix(suffix, "[")
+	item := false
+	if renames, ok := f.Items[t]; ok {
+		// rename the field of an item, e.g., [0].parameterName, or the
+		// field of a block, e.g., .issuerURL
+		i := strings.Index(suffix, "].") + 2
+		if block {
+			i = 1
+		}
+		if i > 1 || (block && strings.HasPrefix(suffix, ".")) {
+			field := suffix[i:]
+			name := field
+			if j := strings.IndexAny(field, ".["); j >= 0 {
+				name = field[:j]
+			}
+			if r, ok := renames[name]; ok {
+				suffix = suffix[:i] + r + strings.TrimPrefix(field, name)
+				item = true
+			}
+		}
+	}
+	if !renamed && !block && !item {
+		return "", false
+	}
+	if block {
+		t += "[0]"
+	}
+	return fmt.Sprintf("%s.%s%s", fieldForProvider, t, suffix), true
+}
+
+// convertPathPrefix converts the given community field path relative to
+// spec.forProvider with the longest matching prefix of the Paths.
+func (f FieldNames) convertPathPrefix(path string) (string, bool) {
+	generic := listIndex.ReplaceAllString(path, "[*]")
+	prefix, wildcard := "", false
+	for p := range f.Paths {
+		if len(p) <= len(prefix) {
+			continue
+		}
+		switch {
+		case hasPathPrefix(path, p):
+			prefix, wildcard = p, false
+		case strings.Contains(p, "[*]") && hasPathPrefix(generic, p):
+			prefix, wildcard = p, true
+		}
+	}
+	if prefix == "" {
+		return "", false
+	}
+	if !wildcard {
+		return f.Paths[prefix] + path[len(prefix):], true
+	}
+	// restore the list indices replaced with the wildcards in order
+	indices := listIndex.FindAllString(path, -1)
+	i := 0
+	return wildcardIndex.ReplaceAllStringFunc(f.Paths[prefix]+generic[len(prefix):], func(w string) string {
+		if i == len(indices) {
+			return w
+		}
+		i++
+		return indices[i-1]
+	}), true
+}
+
+// hasPathPrefix returns true if the given field path starts with the given
+// prefix ending at a field or list index boundary.
+func hasPathPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	rest := path[len(prefix):]
+	return rest == "" || rest[0] == '.' || rest[0] == '['
+}
+
+// CompositionConverter returns a composition converter converting the tags
+// and the patches of the renamed fields.
+func (f FieldNames) CompositionConverter() migration.ComposedTemplateConversionFn {
+	return func(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+		patchesToAdd, err := f.ConvertPatches(sourceTemplate)
+		if err != nil {
+			return err
+		}
+		for i := range convertedTemplates {
+			convertedTemplates[i].Patches = append(convertedTemplates[i].Patches, patchesToAdd...)
+		}
+		return nil
+	}
+}
+
+// ConvertPatches converts the tags and the patches of the renamed fields
+// of the given source template.
+func (f FieldNames) ConvertPatches(sourceTemplate v1.ComposedTemplate) ([]v1.Patch, error) {
+	var patchesToAdd []v1.Patch
+	if !f.TagsMap {
+		var err error
+		if patchesToAdd, err = ConvertComposedTemplateTags(sourceTemplate); err != nil {
+			return nil, errors.Wrap(err, "failed to convert tags")
+		}
+	}
+	for _, p := range sourceTemplate.Patches {
+		switch p.Type { //nolint:exhaustive
+		case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite, "":
+			if to, ok := f.RenameFieldPath(p.ToFieldPath); ok {
+				p.ToFieldPath = &to
+				patchesToAdd = append(patchesToAdd, p)
+			}
+		case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeCombineToComposite:
+			if from, ok := f.RenameFieldPath(p.FromFieldPath); ok {
+				p.FromFieldPath = &from
+				patchesToAdd = append(patchesToAdd, p)
+			}
+		}
+	}
+	return patchesToAdd, nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import "testing"
+
+func TestRenameFieldPath(t *testing.T) {
+	f := FieldNames{
+		Renames: map[string]string{
+			"resourcesVpcConfig": "vpcConfig",
+			"subnets":            "subnetIds",
+		},
+		Blocks: map[string]bool{
+			"vpcConfig":     true,
+			"remoteAccess":  true,
+			"scalingConfig": true,
+		},
+		Items: map[string]map[string]string{
+			"remoteAccess": {
+				"sourceSecurityGroups": "sourceSecurityGroupIds",
+			},
+			"parameter": {
+				"parameterName": "name",
+			},
+		},
+		Inline: map[string]map[string]string{
+			"environment": {
+				"variables": "environmentVariables",
+			},
+		},
+		Paths: map[string]string{
+			"encryptionConfig[*].provider":        "encryptionConfig[*].provider[0]",
+			"outpostConfig":                       "outpostConfig[0]",
+			"outpostConfig.controlPlanePlacement": "outpostConfig[0].controlPlanePlacement[0]",
+		},
+	}
+	type want struct {
+		path    string
+		renamed bool
+	}
+	cases := map[string]struct {
+		path *string
+		want
+	}{
+		"Nil": {},
+		"NotForProvider": {
+			path: ptr("metadata.name"),
+		},
+		"NotConverted": {
+			path: ptr("spec.forProvider.version"),
+		},
+		"Renamed": {
+			path: ptr("spec.forProvider.subnets[1]"),
+			want: want{
+				path:    "spec.forProvider.subnetIds[1]",
+				renamed: true,
+			},
+		},
+		"RenamedBlock": {
+			path: ptr("spec.forProvider.resourcesVpcConfig.endpointPrivateAccess"),
+			want: want{
+				path:    "spec.forProvider.vpcConfig[0].endpointPrivateAccess",
+				renamed: true,
+			},
+		},
+		"Block": {
+			path: ptr("spec.forProvider.scalingConfig.maxSize"),
+			want: want{
+				path:    "spec.forProvider.scalingConfig[0].maxSize",
+				renamed: true,
+			},
+		},
+		"BlockItemField": {
+			path: ptr("spec.forProvider.remoteAccess.sourceSecurityGroups[0]"),
+			want: want{
+				path:    "spec.forProvider.remoteAccess[0].sourceSecurityGroupIds[0]",
+				renamed: true,
+			},
+		},
+		"ListItemField": {
+			path: ptr("spec.forProvider.parameter[2].parameterName"),
+			want: want{
+				path:    "spec.forProvider.parameter[2].name",
+				renamed: true,
+			},
+		},
+		"ListItemFieldNotRenamed": {
+			path: ptr("spec.forProvider.parameter[2].parameterValue"),
+		},
+		"InlinedField": {
+			path: ptr("spec.forProvider.environment.variables[FOO]"),
+			want: want{
+				path:    "spec.forProvider.environmentVariables[FOO]",
+				renamed: true,
+			},
+		},
+		"InlinedObject": {
+			path: ptr("spec.forProvider.environment"),
+		},
+		"Path": {
+			path: ptr("spec.forProvider.outpostConfig.outpostArns[0]"),
+			want: want{
+				path:    "spec.forProvider.outpostConfig[0].outpostArns[0]",
+				renamed: true,
+			},
+		},
+		"LongestPath": {
+			path: ptr("spec.forProvider.outpostConfig.controlPlanePlacement.groupName"),
+			want: want{
+				path:    "spec.forProvider.outpostConfig[0].controlPlanePlacement[0].groupName",
+				renamed: true,
+			},
+		},
+		"WildcardPath": {
+			path: ptr("spec.forProvider.encryptionConfig[3].provider.keyArn"),
+			want: want{
+				path:    "spec.forProvider.encryptionConfig[3].provider[0].keyArn",
+				renamed: true,
+			},
+		},
+		"PathAtFieldBoundary": {
+			path: ptr("spec.forProvider.outpostConfigs"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, renamed := f.RenameFieldPath(tc.path)
+			if got != tc.want.path || renamed != tc.want.renamed {
+				t.Errorf("\nRenameFieldPath(...): want (%q, %t), got (%q, %t)", tc.want.path, tc.want.renamed, got, renamed)
+			}
+		})
+	}
+}
+
+func TestConvertPathPrefix(t *testing.T) {
+	f := FieldNames{
+		Paths: map[string]string{
+			"rule[*].filter":       "rule[*].filter[0]",
+			"rule[*].filter.and":   "rule[*].filter[0].and[0]",
+			"rule[0].expiration":   "rule[0].expirationDays",
+			"website.routingRules": "routingRule",
+		},
+	}
+	type want struct {
+		path      string
+		converted bool
+	}
+	cases := map[string]struct {
+		path string
+		want
+	}{
+		"NoPrefix": {
+			path: "versioning.status",
+		},
+		"Prefix": {
+			path: "website.routingRules[1].condition",
+			want: want{
+				path:      "routingRule[1].condition",
+				converted: true,
+			},
+		},
+		"ExactPrefix": {
+			path: "website.routingRules",
+			want: want{
+				path:      "routingRule",
+				converted: true,
+			},
+		},
+		"PartialFieldName": {
+			path: "website.routingRulesV2",
+		},
+		"Wildcard": {
+			path: "rule[4].filter.prefix",
+			want: want{
+				path:      "rule[4].filter[0].prefix",
+				converted: true,
+			},
+		},
+		"LongestWildcard": {
+			path: "rule[4].filter.and.tags[1]",
+			want: want{
+				path:      "rule[4].filter[0].and[0].tags[1]",
+				converted: true,
+			},
+		},
+		"IndexPreferredOverWildcard": {
+			path: "rule[0].expiration",
+			want: want{
+				path:      "rule[0].expirationDays",
+				converted: true,
+			},
+		},
+		"WildcardNotMatchingOtherIndex": {
+			path: "rule[1].expiration",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, converted := f.convertPathPrefix(tc.path)
+			if got != tc.want.path || converted != tc.want.converted {
+				t.Errorf("\nconvertPathPrefix(%q): want (%q, %t), got (%q, %t)", tc.path, tc.want.path, tc.want.converted, got, converted)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
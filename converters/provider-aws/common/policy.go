@@ -0,0 +1,177 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// iamPolicy is the JSON representation of an IAM policy document.
+type iamPolicy struct {
+	Version   string         `json:"Version,omitempty"`
+	ID        string         `json:"Id,omitempty"`
+	Statement []iamStatement `json:"Statement"`
+}
+
+type iamStatement struct {
+	Sid          string                    `json:"Sid,omitempty"`
+	Effect       string                    `json:"Effect"`
+	Principal    any                       `json:"Principal,omitempty"`
+	NotPrincipal any                       `json:"NotPrincipal,omitempty"`
+	Action       []string                  `json:"Action,omitempty"`
+	NotAction    []string                  `json:"NotAction,omitempty"`
+	Resource     []string                  `json:"Resource,omitempty"`
+	NotResource  []string                  `json:"NotResource,omitempty"`
+	Condition    map[string]map[string]any `json:"Condition,omitempty"`
+}
+
+// awsPrincipalFields maps the fields of the community AWS principals to the
+// formats of the ARNs they represent.
+var awsPrincipalFields = map[string]string{
+	"awsAccountId": "arn:aws:iam::%s:root",
+	"iamUserArn":   "%s",
+	"iamRoleArn":   "%s",
+}
+
+// SerializePolicy serializes the structured policy body of a community
+// resource, such as a Bucket or a RepositoryPolicy, into a JSON policy
+// document. The principals referring to other managed resources cannot be
+// resolved and result in an error.
+func SerializePolicy(v any) (string, error) {
+	body, ok := v.(map[string]any)
+	if !ok {
+		return "", errors.New("policy body is not an object")
+	}
+	p := iamPolicy{
+		Version: stringValue(body["version"]),
+		ID:      stringValue(body["id"]),
+	}
+	statements, _ := body["statements"].([]any)
+	for i, s := range statements {
+		sm, ok := s.(map[string]any)
+		if !ok {
+			return "", errors.Errorf("policy statement at index %d is not an object", i)
+		}
+		st := iamStatement{
+			Sid:         stringValue(sm["sid"]),
+			Effect:      stringValue(sm["effect"]),
+			Action:      stringSlice(sm["action"]),
+			NotAction:   stringSlice(sm["notAction"]),
+			Resource:    stringSlice(sm["resource"]),
+			NotResource: stringSlice(sm["notResource"]),
+		}
+		var err error
+		if st.Principal, err = serializePrincipal(sm["principal"]); err != nil {
+			return "", errors.Wrapf(err, "failed to serialize the principal of the policy statement at index %d", i)
+		}
+		if st.NotPrincipal, err = serializePrincipal(sm["notPrincipal"]); err != nil {
+			return "", errors.Wrapf(err, "failed to serialize the notPrincipal of the policy statement at index %d", i)
+		}
+		st.Condition = serializeConditions(sm["condition"])
+		p.Statement = append(p.Statement, st)
+	}
+	buff, err := json.Marshal(p)
+	return string(buff), errors.Wrap(err, "failed to marshal the policy document")
+}
+
+func serializePrincipal(v any) (any, error) {
+	pm, ok := v.(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	if allow, _ := pm["allowAnon"].(bool); allow {
+		return "*", nil
+	}
+	principal := map[string][]string{}
+	if svc := stringSlice(pm["service"]); len(svc) > 0 {
+		principal["Service"] = svc
+	}
+	awsPrincipals, _ := pm["awsPrincipals"].([]any)
+	for _, a := range awsPrincipals {
+		am, ok := a.(map[string]any)
+		if !ok {
+			continue
+		}
+		for k := range am {
+			if strings.HasSuffix(k, "Ref") || strings.HasSuffix(k, "Selector") {
+				return nil, errors.Errorf("principal field %q refers to another resource and cannot be converted", k)
+			}
+		}
+		for k, f := range awsPrincipalFields {
+			if s := stringValue(am[k]); s != "" {
+				principal["AWS"] = append(principal["AWS"], fmt.Sprintf(f, s))
+			}
+		}
+	}
+	if len(principal) == 0 {
+		return nil, nil
+	}
+	return principal, nil
+}
+
+func serializeConditions(v any) map[string]map[string]any {
+	conditions, _ := v.([]any)
+	if len(conditions) == 0 {
+		return nil
+	}
+	result := make(map[string]map[string]any, len(conditions))
+	for _, c := range conditions {
+		cm, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		op := stringValue(cm["operator"])
+		if result[op] == nil {
+			result[op] = map[string]any{}
+		}
+		pairs, _ := cm["conditions"].([]any)
+		for _, p := range pairs {
+			pm, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+			for _, f := range []string{"stringValue", "booleanValue", "numericValue", "dateValue", "listValue"} {
+				if cv, ok := pm[f]; ok && cv != nil {
+					result[op][stringValue(pm["key"])] = cv
+					break
+				}
+			}
+		}
+	}
+	return result
+}
+
+func stringValue(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func stringSlice(v any) []string {
+	l, _ := v.([]any)
+	result := make([]string, 0, len(l))
+	for _, e := range l {
+		if s, ok := e.(string); ok {
+			result = append(result, s)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ec2
+
+import (
+	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1beta1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+const domainVPC = "vpc"
+
+// AddressFieldNames describes the conversion of the community Address
+// fields into the official EIP fields.
+var AddressFieldNames = providerawscommon.FieldNames{
+	Transform: convertDomain,
+}
+
+// convertDomain converts the domain of the community Address into the vpc
+// flag of the official EIP.
+func convertDomain(forProvider map[string]any) {
+	domain, ok := forProvider["domain"].(string)
+	if !ok {
+		return
+	}
+	delete(forProvider, "domain")
+	forProvider["vpc"] = domain == domainVPC
+}
+
+func AddressResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1beta1.Address)
+	target := &targetv1beta1.EIP{}
+	if err := AddressFieldNames.CopyInto(source, target, targetv1beta1.EIP_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ec2
+
+import (
+	"strings"
+
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+const monitoringEnabled = "enabled"
+
+// InstanceFieldNames describes the conversion of the community Instance
+// fields into the official Instance fields.
+var InstanceFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"blockDeviceMappings":     "ebsBlockDevice",
+		"imageId":                 "ami",
+		"privateIpAddress":        "privateIp",
+		"securityGroupIds":        "vpcSecurityGroupIds",
+		"securityGroupIdRefs":     "vpcSecurityGroupIdRefs",
+		"securityGroupIdSelector": "vpcSecurityGroupIdSelector",
+		"userData":                "userDataBase64",
+	},
+	Blocks: map[string]bool{
+		"creditSpecification": true,
+		"metadataOptions":     true,
+	},
+	Paths:     instanceOptionPaths(),
+	Transform: chain(convertInstanceOptions, convertBlockDevices, tagSpecificationsInto("instance", "tags"), tagSpecificationsInto("volume", "volumeTags")),
+}
+
+// instanceOptions maps the fields of the objects of the community Instance
+// to the top-level fields of the official Instance.
+var instanceOptions = map[string]map[string]string{
+	"cpuOptions": {
+		"coreCount":      "cpuCoreCount",
+		"threadsPerCore": "cpuThreadsPerCore",
+	},
+	"hibernationOptions": {
+		"configured": "hibernation",
+	},
+	"placement": {
+		"availabilityZone": "availabilityZone",
+		"groupName":        "placementGroup",
+		"hostId":           "hostId",
+		"tenancy":          "tenancy",
+	},
+}
+
+// instanceOptionPaths returns the conversions of the field paths of the
+// flattened fields.
+func instanceOptionPaths() map[string]string {
+	paths := map[string]string{
+		"iamInstanceProfile.name": "iamInstanceProfile",
+	}
+	for o, fields := range instanceOptions {
+		for s, t := range fields {
+			paths[o+"."+s] = t
+		}
+	}
+	return paths
+}
+
+// convertInstanceOptions converts the objects of the community Instance
+// that are flattened, or that have a different form, in the official
+// Instance.
+func convertInstanceOptions(forProvider map[string]any) {
+	for o, fields := range instanceOptions {
+		om, ok := forProvider[o].(map[string]any)
+		if !ok {
+			continue
+		}
+		delete(forProvider, o)
+		for s, t := range fields {
+			if v, ok := om[s]; ok {
+				forProvider[t] = v
+			}
+		}
+	}
+	if p, ok := forProvider["iamInstanceProfile"].(map[string]any); ok {
+		delete(forProvider, "iamInstanceProfile")
+		// the official Instance accepts the name of the instance profile
+		if name, ok := p["name"].(string); ok && name != "" {
+			forProvider["iamInstanceProfile"] = name
+		} else if arn, ok := p["arn"].(string); ok && arn != "" {
+			forProvider["iamInstanceProfile"] = arn[strings.LastIndex(arn, "/")+1:]
+		}
+	}
+	if m, ok := forProvider["monitoring"].(map[string]any); ok {
+		forProvider["monitoring"] = m["state"] == monitoringEnabled
+	}
+	if lt, ok := forProvider["launchTemplate"].(map[string]any); ok {
+		b := make(map[string]any)
+		for s, t := range map[string]string{"launchTemplateId": "id", "launchTemplateName": "name", "version": "version"} {
+			if v, ok := lt[s]; ok {
+				b[t] = v
+			}
+		}
+		forProvider["launchTemplate"] = []any{b}
+	}
+}
+
+// convertBlockDevices converts the block device mappings of the community
+// Instance into the EBS and ephemeral block devices of the official
+// Instance. The root device is moved out of the EBS block devices by
+// convertRootBlockDevice.
+func convertBlockDevices(forProvider map[string]any) {
+	mappings, ok := forProvider["ebsBlockDevice"].([]any)
+	if !ok {
+		return
+	}
+	delete(forProvider, "ebsBlockDevice")
+	var ebs, ephemeral []any
+	for i, m := range mappings {
+		mm, ok := m.(map[string]any)
+		if !ok {
+			continue
+		}
+		switch {
+		case mm["ebs"] != nil:
+			d, _ := mm["ebs"].(map[string]any)
+			if d == nil {
+				continue
+			}
+			d["deviceName"] = mm["deviceName"]
+			ebs = append(ebs, d)
+		case mm["virtualName"] != nil:
+			ephemeral = append(ephemeral, map[string]any{
+				"deviceName":  mm["deviceName"],
+				"virtualName": mm["virtualName"],
+			})
+		default:
+			common.Warnf("Instance block device mapping at index %d has neither an EBS volume nor a virtual name and is dropped", i)
+		}
+	}
+	if len(ebs) > 0 {
+		forProvider["ebsBlockDevice"] = ebs
+	}
+	if len(ephemeral) > 0 {
+		forProvider["ephemeralBlockDevice"] = ephemeral
+	}
+}
+
+// convertRootBlockDevice moves the EBS block device of the given official
+// Instance mapped to the root device observed for the given community
+// Instance into its root block device. Changes to the EBS block devices
+// replace the instance, whereas the root device can only be configured
+// with the root block device.
+func convertRootBlockDevice(source *srcv1alpha1.Instance, target *targetv1beta1.Instance) error {
+	su, err := runtime.DefaultUnstructuredConverter.ToUnstructured(source)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the source Instance to unstructured")
+	}
+	rootDeviceName, err := fieldpath.Pave(su).GetString("status.atProvider.rootDeviceName")
+	if err != nil || rootDeviceName == "" {
+		return nil
+	}
+	tu, err := runtime.DefaultUnstructuredConverter.ToUnstructured(target)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the target Instance to unstructured")
+	}
+	pv := fieldpath.Pave(tu)
+	v, err := pv.GetValue("spec.forProvider.ebsBlockDevice")
+	if err != nil {
+		return nil
+	}
+	devices, _ := v.([]any)
+	ebs := make([]any, 0, len(devices))
+	var root map[string]any
+	for _, d := range devices {
+		dm, ok := d.(map[string]any)
+		if ok && root == nil && dm["deviceName"] == rootDeviceName {
+			root = dm
+			continue
+		}
+		ebs = append(ebs, d)
+	}
+	if root == nil {
+		return nil
+	}
+	// the root block device is identified with the AMI of the instance
+	delete(root, "deviceName")
+	delete(root, "snapshotId")
+	if err := pv.SetValue("spec.forProvider.rootBlockDevice", []any{root}); err != nil {
+		return errors.Wrap(err, "failed to set the root block device")
+	}
+	if err := pv.SetValue("spec.forProvider.ebsBlockDevice", ebs); err != nil {
+		return errors.Wrap(err, "failed to set the EBS block devices")
+	}
+	converted := &targetv1beta1.Instance{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(tu, converted); err != nil {
+		return errors.Wrap(err, "failed to convert the root block device")
+	}
+	target.Spec.ForProvider.EBSBlockDevice = converted.Spec.ForProvider.EBSBlockDevice
+	target.Spec.ForProvider.RootBlockDevice = converted.Spec.ForProvider.RootBlockDevice
+	return nil
+}
+
+func InstanceResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.Instance)
+	target := &targetv1beta1.Instance{}
+	if err := InstanceFieldNames.CopyInto(source, target, targetv1beta1.Instance_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	if err := convertRootBlockDevice(source, target); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ec2
+
+import (
+	"fmt"
+	"strings"
+
+	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1beta1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// internetGatewayVPCFields are the fields of the community InternetGateway
+// attaching it to a VPC. They become the fields of a separate official
+// InternetGatewayAttachment.
+var internetGatewayVPCFields = []string{"vpcId", "vpcIdRef", "vpcIdSelector"}
+
+// internetGatewayFieldNames converts the tags of the community
+// InternetGateway.
+var internetGatewayFieldNames = providerawscommon.FieldNames{}
+
+func InternetGatewayResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1beta1.InternetGateway)
+	target := &targetv1beta1.InternetGateway{}
+	skipFields := make([]string, 0, len(internetGatewayVPCFields))
+	for _, f := range internetGatewayVPCFields {
+		skipFields = append(skipFields, "spec.forProvider."+f)
+	}
+	if err := internetGatewayFieldNames.CopyInto(source, target, targetv1beta1.InternetGateway_GroupVersionKind, skipFields...); err != nil {
+		return nil, err
+	}
+	p := source.Spec.ForProvider
+	if p.VPCID == nil && p.VPCIDRef == nil && p.VPCIDSelector == nil {
+		return []resource.Managed{
+			target,
+		}, nil
+	}
+	attachment, err := newInternetGatewayAttachment(source)
+	if err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+		attachment,
+	}, nil
+}
+
+// newInternetGatewayAttachment returns the official
+// InternetGatewayAttachment attaching the given community InternetGateway
+// to its VPC.
+func newInternetGatewayAttachment(source *srcv1beta1.InternetGateway) (*targetv1beta1.InternetGatewayAttachment, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&source.Spec.ForProvider)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert the parameters of the source InternetGateway to unstructured")
+	}
+	forProvider := map[string]any{
+		"region": source.Spec.ForProvider.Region,
+		"internetGatewayIdRef": map[string]any{
+			"name": source.GetName(),
+		},
+		"internetGatewayIdSelector": map[string]any{
+			"matchControllerRef": true,
+		},
+	}
+	for _, f := range internetGatewayVPCFields {
+		if v, ok := u[f]; ok && v != nil {
+			forProvider[f] = v
+		}
+	}
+	externalName := meta.GetExternalName(source)
+	if len(externalName) > 0 {
+		forProvider["internetGatewayId"] = externalName
+	}
+	attachment := &targetv1beta1.InternetGatewayAttachment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]any{
+		"spec": map[string]any{
+			"forProvider": forProvider,
+		},
+	}, attachment); err != nil {
+		return nil, errors.Wrap(err, "failed to convert into InternetGatewayAttachment")
+	}
+	attachment.SetGroupVersionKind(targetv1beta1.InternetGatewayAttachment_GroupVersionKind)
+	if len(source.GetName()) > 0 {
+		attachment.SetName(fmt.Sprintf("%s-attachment", source.GetName()))
+	}
+	if len(source.Labels) > 0 {
+		attachment.Labels = make(map[string]string, len(source.Labels)+1)
+		for k, v := range source.Labels {
+			attachment.Labels[k] = v
+		}
+		attachment.Labels["resourceType"] = targetv1beta1.InternetGatewayAttachment_Kind
+	}
+	// the external name of an InternetGatewayAttachment is <internet gateway ID>:<VPC ID>
+	if len(externalName) > 0 && source.Spec.ForProvider.VPCID != nil {
+		meta.SetExternalName(attachment, fmt.Sprintf("%s:%s", externalName, *source.Spec.ForProvider.VPCID))
+	}
+	attachment.Spec.DeletionPolicy = source.Spec.DeletionPolicy
+	attachment.Spec.ProviderConfigReference = source.Spec.ProviderConfigReference
+	return attachment, nil
+}
+
+// InternetGatewayComposition converts the patches of a composed community
+// InternetGateway. If the InternetGateway is converted together with an
+// InternetGatewayAttachment, the patches of the VPC fields, which are kept
+// on the attachment, are removed from the InternetGateway. Otherwise, they
+// are kept on the InternetGateway, which can also be attached to a VPC.
+func InternetGatewayComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	patchesToAdd, err := internetGatewayFieldNames.ConvertPatches(sourceTemplate)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the InternetGateway patches")
+	}
+	kinds := make([]string, len(convertedTemplates))
+	attached := false
+	for i, t := range convertedTemplates {
+		u, err := migration.FromRawExtension(t.Base)
+		if err != nil {
+			return errors.Wrap(err, "failed to convert ComposedTemplate base")
+		}
+		kinds[i] = u.GetKind()
+		attached = attached || kinds[i] == targetv1beta1.InternetGatewayAttachment_Kind
+	}
+	for i, t := range convertedTemplates {
+		if !attached || kinds[i] != targetv1beta1.InternetGateway_Kind {
+			continue
+		}
+		patches := make([]v1.Patch, 0, len(t.Patches))
+		for _, p := range t.Patches {
+			if !isInternetGatewayVPCPatch(p) {
+				patches = append(patches, p)
+			}
+		}
+		t.Patches = patches
+	}
+	return common.SplittedResourcePatches(convertedTemplates, targetv1beta1.InternetGateway_Kind, patchesToAdd)
+}
+
+// isInternetGatewayVPCPatch returns true if the given patch targets the VPC
+// fields of an InternetGateway.
+func isInternetGatewayVPCPatch(p v1.Patch) bool {
+	path := p.ToFieldPath
+	if p.Type == v1.PatchTypeToCompositeFieldPath || p.Type == v1.PatchTypeCombineToComposite {
+		path = p.FromFieldPath
+	}
+	if path == nil {
+		return false
+	}
+	for _, f := range internetGatewayVPCFields {
+		vf := "spec.forProvider." + f
+		if *path == vf || strings.HasPrefix(*path, vf+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ec2
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// launchTemplateDataFields maps the renamed fields of the launch template
+// data of the community LaunchTemplate to the top-level fields of the
+// official LaunchTemplate.
+var launchTemplateDataFields = map[string]string{
+	"disableAPIStop":        "disableApiStop",
+	"disableAPITermination": "disableApiTermination",
+	"imageID":               "imageId",
+	"kernelID":              "kernelId",
+	"ramDiskID":             "ramDiskId",
+	"securityGroupIDs":      "vpcSecurityGroupIds",
+	"securityGroups":        "securityGroupNames",
+}
+
+// launchTemplateDataBlocks maps the objects of the launch template data
+// that are blocks in the official LaunchTemplate to the renames of their
+// fields.
+var launchTemplateDataBlocks = map[string]map[string]string{
+	"capacityReservationSpecification": nil,
+	"cpuOptions":                       nil,
+	"creditSpecification":              nil,
+	"iamInstanceProfile":               nil,
+	"metadataOptions": {
+		"httpProtocolIPv6": "httpProtocolIpv6",
+	},
+	"monitoring": nil,
+	"placement":  nil,
+}
+
+// launchTemplateItems maps the lists of the launch template data to the
+// renames of the fields of their items.
+var launchTemplateItems = map[string]map[string]string{
+	"networkInterfaces": {
+		"associatePublicIPAddress": "associatePublicIpAddress",
+		"groups":                   "securityGroups",
+		"networkInterfaceID":       "networkInterfaceId",
+		"privateIPAddress":         "privateIpAddress",
+		"subnetID":                 "subnetId",
+	},
+	"ebs": {
+		"kmsKeyID":   "kmsKeyId",
+		"snapshotID": "snapshotId",
+	},
+}
+
+// LaunchTemplateFieldNames describes the conversion of the community
+// LaunchTemplate fields into the official LaunchTemplate fields. The launch
+// template data of the community LaunchTemplate is flattened into the
+// official LaunchTemplate.
+var LaunchTemplateFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"launchTemplateName": "name",
+		"versionDescription": "description",
+	},
+	Paths:     launchTemplateDataPaths(),
+	Transform: chain(tagSpecificationsInto("launch-template", "tags"), convertLaunchTemplateData),
+}
+
+// launchTemplateDataPaths returns the conversions of the field paths of the
+// launch template data.
+func launchTemplateDataPaths() map[string]string {
+	paths := make(map[string]string, len(launchTemplateDataFields))
+	for s, t := range launchTemplateDataFields {
+		paths["launchTemplateData."+s] = t
+	}
+	for _, f := range []string{"instanceType", "keyName", "userData", "ebsOptimized"} {
+		paths["launchTemplateData."+f] = f
+	}
+	return paths
+}
+
+// convertLaunchTemplateData flattens the launch template data of the
+// community LaunchTemplate into the official LaunchTemplate.
+func convertLaunchTemplateData(forProvider map[string]any) {
+	data, ok := forProvider["launchTemplateData"].(map[string]any)
+	if !ok {
+		return
+	}
+	delete(forProvider, "launchTemplateData")
+	for k, v := range data {
+		if t, ok := launchTemplateDataFields[k]; ok {
+			k = t
+		}
+		forProvider[k] = v
+	}
+	for b, renames := range launchTemplateDataBlocks {
+		if m, ok := forProvider[b].(map[string]any); ok {
+			renameFields(m, renames)
+			forProvider[b] = []any{m}
+		}
+	}
+	// the official LaunchTemplate represents these booleans as strings
+	stringifyBool(forProvider, "ebsOptimized")
+	mappings, _ := forProvider["blockDeviceMappings"].([]any)
+	for _, m := range mappings {
+		mm, ok := m.(map[string]any)
+		if !ok {
+			continue
+		}
+		if ebs, ok := mm["ebs"].(map[string]any); ok {
+			renameFields(ebs, launchTemplateItems["ebs"])
+			stringifyBool(ebs, "deleteOnTermination")
+			stringifyBool(ebs, "encrypted")
+			mm["ebs"] = []any{ebs}
+		}
+	}
+	interfaces, _ := forProvider["networkInterfaces"].([]any)
+	for _, i := range interfaces {
+		if im, ok := i.(map[string]any); ok {
+			renameFields(im, launchTemplateItems["networkInterfaces"])
+			stringifyBool(im, "associatePublicIpAddress")
+			stringifyBool(im, "deleteOnTermination")
+		}
+	}
+	specs, _ := forProvider["tagSpecifications"].([]any)
+	for _, s := range specs {
+		sm, ok := s.(map[string]any)
+		if !ok {
+			continue
+		}
+		l, _ := sm["tags"].([]any)
+		tags := make(map[string]any, len(l))
+		for _, t := range l {
+			if tm, ok := t.(map[string]any); ok {
+				if k, ok := tm["key"].(string); ok {
+					tags[k] = tm["value"]
+				}
+			}
+		}
+		sm["tags"] = tags
+	}
+}
+
+func renameFields(m map[string]any, renames map[string]string) {
+	for s, t := range renames {
+		if v, ok := m[s]; ok {
+			delete(m, s)
+			m[t] = v
+		}
+	}
+}
+
+func stringifyBool(m map[string]any, field string) {
+	if b, ok := m[field].(bool); ok {
+		if b {
+			m[field] = "true"
+		} else {
+			m[field] = "false"
+		}
+	}
+}
+
+func LaunchTemplateResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.LaunchTemplate)
+	target := &targetv1beta1.LaunchTemplate{}
+	if err := LaunchTemplateFieldNames.CopyInto(source, target, targetv1beta1.LaunchTemplate_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
+
+// LaunchTemplateVersionResource drops the given community
+// LaunchTemplateVersion as the official provider has no launch template
+// version resource. Instead, the official LaunchTemplate creates a new
+// version whenever its data changes, so a warning is reported for the
+// launch template data of the latest version to be set on the official
+// LaunchTemplate, which otherwise publishes the data of the community
+// LaunchTemplate as its new default version.
+func LaunchTemplateVersionResource(mg resource.Managed) ([]resource.Managed, error) {
+	common.Warnf("LaunchTemplateVersion %q is not converted as it has no official counterpart, set the launch template data of the latest version on the converted LaunchTemplate", mg.GetName())
+	return nil, nil
+}
+
+// LaunchTemplateVersionComposition reports a warning for a composed community
+// LaunchTemplateVersion, which is not converted together with its patches.
+func LaunchTemplateVersionComposition(sourceTemplate v1.ComposedTemplate, _ ...*v1.ComposedTemplate) error {
+	name := ""
+	if sourceTemplate.Name != nil {
+		name = *sourceTemplate.Name
+	}
+	common.Warnf("Composed LaunchTemplateVersion %q is not converted as it has no official counterpart, compose the launch template data of the latest version with the LaunchTemplate", name)
+	return nil
+}
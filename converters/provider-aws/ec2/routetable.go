@@ -15,12 +15,18 @@
 package ec2
 
 import (
+	"sort"
+	"strings"
+
 	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1beta1"
 	runtimev1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/upjet/pkg/migration"
 	"github.com/pkg/errors"
 	targetv1beta1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/upbound/extensions-migration/converters/common"
 )
 
 func RouteTableResource(mg resource.Managed) ([]resource.Managed, error) {
@@ -61,32 +67,147 @@ func RouteTableResource(mg resource.Managed) ([]resource.Managed, error) {
 			routeTableMRs = append(routeTableMRs, rtAssociation)
 		}
 	}
-	if source.Spec.ForProvider.Routes != nil {
-		for _, route := range source.Spec.ForProvider.Routes {
-			rtRoute := &targetv1beta1.Route{}
-			rtRoute.SetGroupVersionKind(targetv1beta1.Route_GroupVersionKind)
-			rtRoute.Labels = source.Labels
-			rtRoute.Labels["resourceType"] = "Route"
-			rtRoute.Spec.DeletionPolicy = source.Spec.DeletionPolicy
-			rtRoute.Spec.ForProvider.Region = &source.Spec.ForProvider.Region
-			rtRoute.Spec.ForProvider.NATGatewayID = route.NatGatewayID
-			rtRoute.Spec.ForProvider.NATGatewayIDSelector = route.NatGatewayIDSelector
-			rtRoute.Spec.ForProvider.NATGatewayIDRef = route.NatGatewayIDRef
-			rtRoute.Spec.ForProvider.DestinationCidrBlock = route.DestinationCIDRBlock
-			rtRoute.Spec.ForProvider.TransitGatewayID = route.TransitGatewayID
-			matchController := true
-			if len(externalNameRouteTableId) > 0 {
-				rtRoute.Spec.ForProvider.RouteTableID = &externalNameRouteTableId
-			}
-			rtRoute.Spec.ForProvider.RouteTableIDRef = &runtimev1.Reference{}
-			rtRoute.Spec.ForProvider.RouteTableIDRef.Name = NameRouteTable
-			rtRoute.Spec.ForProvider.RouteTableIDSelector = &runtimev1.Selector{}
-			rtRoute.Spec.ForProvider.RouteTableIDSelector.MatchLabels = source.Labels
-			rtRoute.Spec.ForProvider.RouteTableIDSelector.MatchControllerRef = &matchController
-			rtRoute.Spec.ProviderConfigReference = source.Spec.ProviderConfigReference
-			routeTableMRs = append(routeTableMRs, rtRoute)
+	for i, route := range source.Spec.ForProvider.Routes {
+		rtRoute, err := convertRoute(source, i, route)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the route at index %d", i)
 		}
-
+		if rtRoute == nil {
+			continue
+		}
+		matchController := true
+		if len(externalNameRouteTableId) > 0 {
+			rtRoute.Spec.ForProvider.RouteTableID = &externalNameRouteTableId
+		}
+		rtRoute.Spec.ForProvider.RouteTableIDRef = &runtimev1.Reference{}
+		rtRoute.Spec.ForProvider.RouteTableIDRef.Name = NameRouteTable
+		rtRoute.Spec.ForProvider.RouteTableIDSelector = &runtimev1.Selector{}
+		rtRoute.Spec.ForProvider.RouteTableIDSelector.MatchLabels = source.Labels
+		rtRoute.Spec.ForProvider.RouteTableIDSelector.MatchControllerRef = &matchController
+		rtRoute.Spec.ProviderConfigReference = source.Spec.ProviderConfigReference
+		routeTableMRs = append(routeTableMRs, rtRoute)
 	}
 	return routeTableMRs, nil
 }
+
+// routeFields maps the fields of the community routes to the fields of the
+// official Route.
+var routeFields = map[string]string{
+	"destinationCidrBlock":           "destinationCidrBlock",
+	"destinationIpv6CidrBlock":       "destinationIpv6CidrBlock",
+	"destinationPrefixListId":        "destinationPrefixListId",
+	"carrierGatewayId":               "carrierGatewayId",
+	"egressOnlyInternetGatewayId":    "egressOnlyGatewayId",
+	"gatewayId":                      "gatewayId",
+	"gatewayIdRef":                   "gatewayIdRef",
+	"gatewayIdSelector":              "gatewayIdSelector",
+	"localGatewayId":                 "localGatewayId",
+	"natGatewayId":                   "natGatewayId",
+	"natGatewayIdRef":                "natGatewayIdRef",
+	"natGatewayIdSelector":           "natGatewayIdSelector",
+	"networkInterfaceId":             "networkInterfaceId",
+	"networkInterfaceIdRef":          "networkInterfaceIdRef",
+	"networkInterfaceIdSelector":     "networkInterfaceIdSelector",
+	"transitGatewayId":               "transitGatewayId",
+	"transitGatewayIdRef":            "transitGatewayIdRef",
+	"transitGatewayIdSelector":       "transitGatewayIdSelector",
+	"vpcEndpointId":                  "vpcEndpointId",
+	"vpcEndpointIdRef":               "vpcEndpointIdRef",
+	"vpcEndpointIdSelector":          "vpcEndpointIdSelector",
+	"vpcPeeringConnectionId":         "vpcPeeringConnectionId",
+	"vpcPeeringConnectionIdRef":      "vpcPeeringConnectionIdRef",
+	"vpcPeeringConnectionIdSelector": "vpcPeeringConnectionIdSelector",
+}
+
+// routeDestinations are the official Route fields specifying a destination.
+var routeDestinations = []string{"destinationCidrBlock", "destinationIpv6CidrBlock", "destinationPrefixListId"}
+
+// convertRoute converts the community route at the given index into an
+// official Route. The route fields that have no official counterpart, such
+// as the instance targets, are reported as warnings. If the route has no
+// destination or no target, a warning is reported and nil is returned.
+func convertRoute(source *srcv1alpha1.RouteTable, i int, route srcv1alpha1.RouteBeta) (*targetv1beta1.Route, error) {
+	src, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&route)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert the route to unstructured")
+	}
+	forProvider := make(map[string]any, len(src))
+	keys := make([]string, 0, len(src))
+	for k := range src {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var targets []string
+	for _, k := range keys {
+		if src[k] == nil {
+			continue
+		}
+		t, ok := routeFields[k]
+		if !ok {
+			common.Warnf("RouteTable %q: route at index %d: field %q cannot be converted to the official Route and is dropped", source.GetName(), i, k)
+			continue
+		}
+		forProvider[t] = src[k]
+		if !isRouteDestination(t) {
+			targets = append(targets, strings.TrimSuffix(strings.TrimSuffix(t, "Ref"), "Selector"))
+		}
+	}
+	hasDestination := false
+	for _, d := range routeDestinations {
+		if _, ok := forProvider[d]; ok {
+			hasDestination = true
+		}
+	}
+	targets = uniqueStrings(targets)
+	switch {
+	case !hasDestination:
+		common.Warnf("RouteTable %q: route at index %d has no destination and is skipped", source.GetName(), i)
+		return nil, nil
+	case len(targets) == 0:
+		common.Warnf("RouteTable %q: route at index %d has no supported target and is skipped", source.GetName(), i)
+		return nil, nil
+	case len(targets) > 1:
+		common.Warnf("RouteTable %q: route at index %d has multiple targets %v, which the official Route does not accept", source.GetName(), i, targets)
+	}
+
+	rtRoute := &targetv1beta1.Route{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]any{
+		"spec": map[string]any{
+			"forProvider": forProvider,
+		},
+	}, rtRoute); err != nil {
+		return nil, errors.Wrap(err, "failed to convert into Route")
+	}
+	rtRoute.SetGroupVersionKind(targetv1beta1.Route_GroupVersionKind)
+	if len(source.Labels) > 0 {
+		rtRoute.Labels = make(map[string]string, len(source.Labels)+1)
+		for k, v := range source.Labels {
+			rtRoute.Labels[k] = v
+		}
+		rtRoute.Labels["resourceType"] = "Route"
+	}
+	rtRoute.Spec.DeletionPolicy = source.Spec.DeletionPolicy
+	rtRoute.Spec.ForProvider.Region = &source.Spec.ForProvider.Region
+	return rtRoute, nil
+}
+
+func isRouteDestination(field string) bool {
+	for _, d := range routeDestinations {
+		if d == field {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueStrings(l []string) []string {
+	seen := make(map[string]bool, len(l))
+	result := make([]string, 0, len(l))
+	for _, s := range l {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
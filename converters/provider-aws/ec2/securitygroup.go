@@ -15,8 +15,16 @@
 package ec2
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
 	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1beta1"
 	runtimev1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/upjet/pkg/migration"
@@ -27,6 +35,11 @@ import (
 	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
 )
 
+const (
+	ruleTypeIngress = "ingress"
+	ruleTypeEgress  = "egress"
+)
+
 func SecurityGroupResource(mg resource.Managed) ([]resource.Managed, error) {
 	source := mg.(*srcv1beta1.SecurityGroup)
 	target := &targetv1beta1.SecurityGroup{}
@@ -40,56 +53,149 @@ func SecurityGroupResource(mg resource.Managed) ([]resource.Managed, error) {
 	}
 	target.Spec.ForProvider.Name = &source.Spec.ForProvider.GroupName
 
-	externalNameSecurityGroupId := source.Annotations["crossplane.io/external-name"]
-	nameSecurityGroup := source.Name
-
 	secGroupMRs := []resource.Managed{target}
-	if source.Spec.ForProvider.Ingress != nil {
-		for _, rule := range source.Spec.ForProvider.Ingress {
-			sgRule := &targetv1beta1.SecurityGroupRule{}
-			sgRule.SetGroupVersionKind(targetv1beta1.SecurityGroupRule_GroupVersionKind)
-			sgRule.Spec.ForProvider.Type = common.PtrFromString("ingress")
-			sgRule.Spec.ForProvider.Region = source.Spec.ForProvider.Region
-			sgRule.Labels = source.Labels
-			if len(source.Labels) > 0 {
-				sgRule.Labels["resourceType"] = "SecurityGroupRule"
-			}
-			sgRule.Spec.ForProvider.Protocol = &rule.IPProtocol
-			if rule.FromPort != nil {
-				convert := float64(*rule.FromPort)
-				sgRule.Spec.ForProvider.FromPort = &convert
-			}
-			if rule.ToPort != nil {
-				convert := float64(*rule.ToPort)
-				sgRule.Spec.ForProvider.ToPort = &convert
-			}
-			if rule.UserIDGroupPairs != nil {
-				for _, sourceSecGroup := range rule.UserIDGroupPairs {
-					sgRule.Spec.ForProvider.SourceSecurityGroupID = sourceSecGroup.GroupID
-				}
-			}
-
-			if rule.IPRanges != nil {
-				for _, ipRanges := range rule.IPRanges {
-					sgRule.Spec.ForProvider.CidrBlocks = append(sgRule.Spec.ForProvider.CidrBlocks, common.PtrFromString(ipRanges.CIDRIP))
-				}
-			}
-
-			matchController := true
-			if len(externalNameSecurityGroupId) > 0 {
-				sgRule.Spec.ForProvider.SecurityGroupID = &externalNameSecurityGroupId
-			}
-			sgRule.Spec.ForProvider.SecurityGroupIDRef = &runtimev1.Reference{}
-			sgRule.Spec.ForProvider.SecurityGroupIDRef.Name = nameSecurityGroup
-			sgRule.Spec.ForProvider.SecurityGroupIDSelector = &runtimev1.Selector{}
-			sgRule.Spec.ForProvider.SecurityGroupIDSelector.MatchControllerRef = &matchController
-			sgRule.Spec.ProviderConfigReference = source.Spec.ProviderConfigReference
-			secGroupMRs = append(secGroupMRs, sgRule)
-		}
+	for _, rule := range source.Spec.ForProvider.Ingress {
+		secGroupMRs = append(secGroupMRs, securityGroupRules(source, ruleTypeIngress, rule)...)
+	}
+	for _, rule := range source.Spec.ForProvider.Egress {
+		secGroupMRs = append(secGroupMRs, securityGroupRules(source, ruleTypeEgress, rule)...)
 	}
 	return secGroupMRs, nil
 }
 
+// securityGroupRules converts the given community IP permission into one
+// SecurityGroupRule per IPv4 range, IPv6 range, prefix list and peer
+// security group so that each rule keeps its own description.
+func securityGroupRules(source *srcv1beta1.SecurityGroup, ruleType string, rule srcv1beta1.IPPermission) []resource.Managed {
+	var rules []*targetv1beta1.SecurityGroupRule
+	for _, r := range rule.IPRanges {
+		sgRule := newSecurityGroupRule(source, ruleType, rule, r.Description)
+		sgRule.Spec.ForProvider.CidrBlocks = []*string{common.PtrFromString(r.CIDRIP)}
+		rules = append(rules, sgRule)
+	}
+	for _, r := range rule.IPv6Ranges {
+		sgRule := newSecurityGroupRule(source, ruleType, rule, r.Description)
+		sgRule.Spec.ForProvider.IPv6CidrBlocks = []*string{common.PtrFromString(r.CIDRIPv6)}
+		rules = append(rules, sgRule)
+	}
+	for _, p := range rule.PrefixListIDs {
+		sgRule := newSecurityGroupRule(source, ruleType, rule, p.Description)
+		sgRule.Spec.ForProvider.PrefixListIds = []*string{common.PtrFromString(p.PrefixListID)}
+		rules = append(rules, sgRule)
+	}
+	externalName := meta.GetExternalName(source)
+	for _, pair := range rule.UserIDGroupPairs {
+		sgRule := newSecurityGroupRule(source, ruleType, rule, pair.Description)
+		switch {
+		case pair.GroupID != nil && len(externalName) > 0 && *pair.GroupID == externalName,
+			pair.GroupIDRef != nil && len(source.GetName()) > 0 && pair.GroupIDRef.Name == source.GetName():
+			self := true
+			sgRule.Spec.ForProvider.Self = &self
+		default:
+			sgRule.Spec.ForProvider.SourceSecurityGroupID = pair.GroupID
+			sgRule.Spec.ForProvider.SourceSecurityGroupIDRef = pair.GroupIDRef
+			sgRule.Spec.ForProvider.SourceSecurityGroupIDSelector = pair.GroupIDSelector
+		}
+		rules = append(rules, sgRule)
+	}
+
+	result := make([]resource.Managed, 0, len(rules))
+	for _, r := range rules {
+		if len(source.GetName()) > 0 {
+			r.SetName(fmt.Sprintf("%s-%s-%s", source.GetName(), ruleType, securityGroupRuleHash(r)))
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+func newSecurityGroupRule(source *srcv1beta1.SecurityGroup, ruleType string, rule srcv1beta1.IPPermission, description *string) *targetv1beta1.SecurityGroupRule {
+	sgRule := &targetv1beta1.SecurityGroupRule{}
+	sgRule.SetGroupVersionKind(targetv1beta1.SecurityGroupRule_GroupVersionKind)
+	if len(source.Labels) > 0 {
+		sgRule.Labels = make(map[string]string, len(source.Labels)+1)
+		for k, v := range source.Labels {
+			sgRule.Labels[k] = v
+		}
+		sgRule.Labels["resourceType"] = "SecurityGroupRule"
+	}
+	sgRule.Spec.DeletionPolicy = source.Spec.DeletionPolicy
+	sgRule.Spec.ForProvider.Type = common.PtrFromString(ruleType)
+	sgRule.Spec.ForProvider.Region = source.Spec.ForProvider.Region
+	sgRule.Spec.ForProvider.Description = description
+	protocol := rule.IPProtocol
+	sgRule.Spec.ForProvider.Protocol = &protocol
+	sgRule.Spec.ForProvider.FromPort = common.PtrFloat64FromInt32(rule.FromPort)
+	sgRule.Spec.ForProvider.ToPort = common.PtrFloat64FromInt32(rule.ToPort)
+	// the "all" protocol requires the whole port range
+	if protocol == "-1" || protocol == "all" {
+		all := float64(0)
+		if sgRule.Spec.ForProvider.FromPort == nil {
+			sgRule.Spec.ForProvider.FromPort = &all
+		}
+		if sgRule.Spec.ForProvider.ToPort == nil {
+			sgRule.Spec.ForProvider.ToPort = &all
+		}
+	}
+
+	matchController := true
+	if externalName := meta.GetExternalName(source); len(externalName) > 0 {
+		sgRule.Spec.ForProvider.SecurityGroupID = &externalName
+	}
+	sgRule.Spec.ForProvider.SecurityGroupIDRef = &runtimev1.Reference{}
+	sgRule.Spec.ForProvider.SecurityGroupIDRef.Name = source.Name
+	sgRule.Spec.ForProvider.SecurityGroupIDSelector = &runtimev1.Selector{}
+	sgRule.Spec.ForProvider.SecurityGroupIDSelector.MatchControllerRef = &matchController
+	sgRule.Spec.ProviderConfigReference = source.Spec.ProviderConfigReference
+	return sgRule
+}
+
+// securityGroupRuleHash returns a short hash identifying the given rule
+// within its security group, which is stable across migration plan runs.
+func securityGroupRuleHash(r *targetv1beta1.SecurityGroupRule) string {
+	p := r.Spec.ForProvider
+	fields := []string{
+		ptrString(p.Type), ptrString(p.Protocol), ptrFloat(p.FromPort), ptrFloat(p.ToPort),
+		ptrStrings(p.CidrBlocks), ptrStrings(p.IPv6CidrBlocks), ptrStrings(p.PrefixListIds),
+		ptrString(p.SourceSecurityGroupID), fmt.Sprintf("%v", p.Self != nil && *p.Self),
+	}
+	if p.SourceSecurityGroupIDRef != nil {
+		fields = append(fields, p.SourceSecurityGroupIDRef.Name)
+	}
+	if p.SourceSecurityGroupIDSelector != nil {
+		keys := make([]string, 0, len(p.SourceSecurityGroupIDSelector.MatchLabels))
+		for k, v := range p.SourceSecurityGroupIDSelector.MatchLabels {
+			keys = append(keys, k+"="+v)
+		}
+		sort.Strings(keys)
+		fields = append(fields, keys...)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "/")))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+func ptrString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func ptrFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
+func ptrStrings(l []*string) string {
+	s := make([]string, 0, len(l))
+	for _, e := range l {
+		s = append(s, ptrString(e))
+	}
+	return strings.Join(s, ",")
+}
+
 func SecurityGroupComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
 	patchesToAdd, err := providerawscommon.ConvertComposedTemplateTags(sourceTemplate)
 	if err != nil {
@@ -0,0 +1,70 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ec2
+
+// tagSpecificationsInto returns a transformation moving the tags of the tag
+// specifications of the given resource type in the community parameters
+// into the given map field of the official parameters.
+func tagSpecificationsInto(resourceType, field string) func(map[string]any) {
+	return func(forProvider map[string]any) {
+		specs, ok := forProvider["tagSpecifications"].([]any)
+		if !ok {
+			return
+		}
+		tags, _ := forProvider[field].(map[string]any)
+		if tags == nil {
+			tags = make(map[string]any)
+		}
+		var rest []any
+		for _, s := range specs {
+			sm, ok := s.(map[string]any)
+			if !ok {
+				continue
+			}
+			if rt, ok := sm["resourceType"].(string); ok && rt != resourceType {
+				rest = append(rest, s)
+				continue
+			}
+			l, _ := sm["tags"].([]any)
+			for _, t := range l {
+				tm, ok := t.(map[string]any)
+				if !ok {
+					continue
+				}
+				if k, ok := tm["key"].(string); ok {
+					tags[k] = tm["value"]
+				}
+			}
+		}
+		if len(rest) > 0 {
+			forProvider["tagSpecifications"] = rest
+		} else {
+			delete(forProvider, "tagSpecifications")
+		}
+		if len(tags) > 0 {
+			forProvider[field] = tags
+		}
+	}
+}
+
+// chain returns a transformation applying the given transformations in
+// order.
+func chain(fns ...func(map[string]any)) func(map[string]any) {
+	return func(forProvider map[string]any) {
+		for _, fn := range fns {
+			fn(forProvider)
+		}
+	}
+}
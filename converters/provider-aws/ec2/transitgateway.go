@@ -0,0 +1,49 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ec2
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// TransitGatewayFieldNames describes the conversion of the community
+// TransitGateway fields into the official TransitGateway fields. The
+// options of the community TransitGateway are top-level fields of the
+// official TransitGateway.
+var TransitGatewayFieldNames = providerawscommon.FieldNames{
+	Inline: map[string]map[string]string{
+		"options": {
+			"amazonSideASN":            "amazonSideAsn",
+			"transitGatewayCIDRBlocks": "transitGatewayCidrBlocks",
+			"vpnECMPSupport":           "vpnEcmpSupport",
+		},
+	},
+	Transform: tagSpecificationsInto("transit-gateway", "tags"),
+}
+
+func TransitGatewayResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.TransitGateway)
+	target := &targetv1beta1.TransitGateway{}
+	if err := TransitGatewayFieldNames.CopyInto(source, target, targetv1beta1.TransitGateway_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ec2
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/ec2/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// VPCPeeringConnectionFieldNames describes the conversion of the community
+// VPCPeeringConnection fields into the official VPCPeeringConnection
+// fields.
+var VPCPeeringConnectionFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"acceptRequest":     "autoAccept",
+		"peerOwnerID":       "peerOwnerId",
+		"peerVPCID":         "peerVpcId",
+		"peerVPCIDRef":      "peerVpcIdRef",
+		"peerVPCIDSelector": "peerVpcIdSelector",
+		"vpcID":             "vpcId",
+		"vpcIDRef":          "vpcIdRef",
+		"vpcIDSelector":     "vpcIdSelector",
+	},
+	Transform: tagSpecificationsInto("vpc-peering-connection", "tags"),
+}
+
+func VPCPeeringConnectionResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.VPCPeeringConnection)
+	target := &targetv1beta1.VPCPeeringConnection{}
+	if err := VPCPeeringConnectionFieldNames.CopyInto(source, target, targetv1beta1.VPCPeeringConnection_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecr
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/ecr/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/ecr/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// lifecyclePolicyFieldNames describes the conversion of the community
+// LifecyclePolicy fields into the official LifecyclePolicy fields. The
+// policy text is converted separately.
+var lifecyclePolicyFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"repositoryName":         "repository",
+		"repositoryNameRef":      "repositoryRef",
+		"repositoryNameSelector": "repositorySelector",
+	},
+}
+
+func LifecyclePolicyResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.LifecyclePolicy)
+	target := &targetv1beta1.LifecyclePolicy{}
+	if err := lifecyclePolicyFieldNames.CopyInto(source, target, targetv1beta1.LifecyclePolicy_GroupVersionKind,
+		"spec.forProvider.lifecyclePolicyText"); err != nil {
+		return nil, err
+	}
+	if text := source.Spec.ForProvider.LifecyclePolicyText; text != nil && *text != "" {
+		policy, err := normalizeJSON(*text)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to normalize the lifecycle policy text")
+		}
+		target.Spec.ForProvider.Policy = &policy
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
+
+// normalizeJSON returns the compact form of the given JSON document with
+// its object keys sorted, so that the semantically equal documents are
+// represented the same.
+func normalizeJSON(doc string) (string, error) {
+	var v any
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal the JSON document")
+	}
+	buff := &bytes.Buffer{}
+	enc := json.NewEncoder(buff)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", errors.Wrap(err, "failed to marshal the JSON document")
+	}
+	return strings.TrimSuffix(buff.String(), "\n"), nil
+}
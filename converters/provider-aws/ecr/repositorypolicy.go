@@ -0,0 +1,72 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecr
+
+import (
+	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/ecr/v1beta1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/ecr/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// repositoryPolicyFieldNames describes the conversion of the community
+// RepositoryPolicy fields into the official RepositoryPolicy fields. The
+// policy is converted separately.
+var repositoryPolicyFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"repositoryName":         "repository",
+		"repositoryNameRef":      "repositoryRef",
+		"repositoryNameSelector": "repositorySelector",
+	},
+}
+
+func RepositoryPolicyResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1beta1.RepositoryPolicy)
+	target := &targetv1beta1.RepositoryPolicy{}
+	if err := repositoryPolicyFieldNames.CopyInto(source, target, targetv1beta1.RepositoryPolicy_GroupVersionKind,
+		"spec.forProvider.policy", "spec.forProvider.rawPolicy"); err != nil {
+		return nil, err
+	}
+	policy, err := repositoryPolicy(source)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert the policy of the RepositoryPolicy")
+	}
+	if policy != "" {
+		target.Spec.ForProvider.Policy = &policy
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
+
+// repositoryPolicy returns the normalized JSON policy document of the given
+// community RepositoryPolicy. The raw policy takes precedence over the
+// structured policy.
+func repositoryPolicy(source *srcv1beta1.RepositoryPolicy) (string, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&source.Spec.ForProvider)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to convert the parameters to unstructured")
+	}
+	if raw, ok := u["rawPolicy"].(string); ok && raw != "" {
+		return normalizeJSON(raw)
+	}
+	if p, ok := u["policy"]; ok && p != nil {
+		return providerawscommon.SerializePolicy(p)
+	}
+	return "", nil
+}
@@ -17,44 +17,88 @@ package eks
 import (
 	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/eks/v1beta1"
 	runtimev1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/upjet/pkg/migration"
 	"github.com/pkg/errors"
 	targetv1beta1 "github.com/upbound/provider-aws/apis/eks/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
 )
 
+// ClusterResource converts a community Cluster into an official Cluster and
+// a ClusterAuth publishing the connection secret of the community Cluster.
 func ClusterResource(mg resource.Managed) ([]resource.Managed, error) {
 	source := mg.(*srcv1beta1.Cluster)
 	target := &targetv1beta1.Cluster{}
-	if _, err := migration.CopyInto(source, target, targetv1beta1.Cluster_GroupVersionKind); err != nil {
+	if _, err := migration.CopyInto(source, target, targetv1beta1.Cluster_GroupVersionKind,
+		"spec.forProvider.resourcesVpcConfig", "spec.forProvider.logging", "spec.forProvider.encryptionConfig",
+		"spec.forProvider.kubernetesNetworkConfig", "spec.forProvider.outpostConfig"); err != nil {
 		return nil, errors.Wrap(err, "failed to copy source into target")
 	}
 
 	eksMRs := []resource.Managed{target}
 
+	rvc := source.Spec.ForProvider.ResourcesVpcConfig
 	vpcConfig := targetv1beta1.VPCConfigParameters{
-		EndpointPrivateAccess: source.Spec.ForProvider.ResourcesVpcConfig.EndpointPrivateAccess,
-		EndpointPublicAccess:  source.Spec.ForProvider.ResourcesVpcConfig.EndpointPublicAccess,
-		SubnetIds:             make([]*string, 0),
-		SubnetIDRefs:          append([]runtimev1.Reference{}, source.Spec.ForProvider.ResourcesVpcConfig.SubnetIDRefs...),
-		SubnetIDSelector:      source.Spec.ForProvider.ResourcesVpcConfig.SubnetIDSelector,
+		EndpointPrivateAccess:   rvc.EndpointPrivateAccess,
+		EndpointPublicAccess:    rvc.EndpointPublicAccess,
+		SubnetIds:               make([]*string, 0, len(rvc.SubnetIDs)),
+		SubnetIDRefs:            append([]runtimev1.Reference{}, rvc.SubnetIDRefs...),
+		SubnetIDSelector:        rvc.SubnetIDSelector,
+		SecurityGroupIDRefs:     append([]runtimev1.Reference{}, rvc.SecurityGroupIDRefs...),
+		SecurityGroupIDSelector: rvc.SecurityGroupIDSelector,
+	}
+	for _, subnet := range rvc.SubnetIDs {
+		vpcConfig.SubnetIds = append(vpcConfig.SubnetIds, common.PtrFromString(subnet))
+	}
+	for _, sg := range rvc.SecurityGroupIDs {
+		vpcConfig.SecurityGroupIds = append(vpcConfig.SecurityGroupIds, common.PtrFromString(sg))
 	}
+	for _, cidr := range rvc.PublicAccessCidrs {
+		vpcConfig.PublicAccessCidrs = append(vpcConfig.PublicAccessCidrs, common.PtrFromString(cidr))
+	}
+	target.Spec.ForProvider.VPCConfig = []targetv1beta1.VPCConfigParameters{vpcConfig}
 
-	for _, subnet := range source.Spec.ForProvider.ResourcesVpcConfig.SubnetIDs {
-		vpcConfig.SubnetIds = append(vpcConfig.SubnetIds, &subnet)
+	if source.Spec.ForProvider.Logging != nil {
+		for _, l := range source.Spec.ForProvider.Logging.ClusterLogging {
+			if l.Enabled != nil && !*l.Enabled {
+				continue
+			}
+			for _, t := range l.Types {
+				target.Spec.ForProvider.EnabledClusterLogTypes = append(target.Spec.ForProvider.EnabledClusterLogTypes, common.PtrFromString(string(t)))
+			}
+		}
 	}
 
-	target.Spec.ForProvider.VPCConfig = []targetv1beta1.VPCConfigParameters{vpcConfig}
+	blocks, err := convertClusterBlocks(source)
+	if err != nil {
+		return nil, err
+	}
+	target.Spec.ForProvider.EncryptionConfig = blocks.Spec.ForProvider.EncryptionConfig
+	target.Spec.ForProvider.KubernetesNetworkConfig = blocks.Spec.ForProvider.KubernetesNetworkConfig
+	target.Spec.ForProvider.OutpostConfig = blocks.Spec.ForProvider.OutpostConfig
+
 	// unset because official provider uses ClusterAuth resource for connectionSecret
 	target.Spec.WriteConnectionSecretToReference = nil
 
 	if source.Spec.WriteConnectionSecretToReference != nil {
 		clusterAuth := &targetv1beta1.ClusterAuth{}
 		clusterAuth.SetGroupVersionKind(targetv1beta1.ClusterAuth_GroupVersionKind)
-		clusterAuth.Labels = source.Labels
-		clusterAuth.Labels["resourceType"] = "ClusterAuth"
+		if len(source.Labels) > 0 {
+			clusterAuth.Labels = make(map[string]string, len(source.Labels)+1)
+			for k, v := range source.Labels {
+				clusterAuth.Labels[k] = v
+			}
+			clusterAuth.Labels["resourceType"] = "ClusterAuth"
+		}
 		clusterAuth.Spec.DeletionPolicy = source.Spec.DeletionPolicy
-		clusterAuth.Spec.ForProvider.Region = *source.Spec.ForProvider.Region
+		if source.Spec.ForProvider.Region != nil {
+			clusterAuth.Spec.ForProvider.Region = *source.Spec.ForProvider.Region
+		}
 		clusterAuth.Spec.ForProvider.ClusterNameSelector = &runtimev1.Selector{}
 		clusterAuth.Spec.ForProvider.ClusterNameSelector.MatchLabels = source.Labels
 		matchController := true
@@ -66,3 +110,90 @@ func ClusterResource(mg resource.Managed) ([]resource.Managed, error) {
 
 	return eksMRs, nil
 }
+
+// clusterBlocks are the community Cluster objects, nested in the parameters
+// or in the items of the parameter lists, that are blocks in the official
+// Cluster.
+var clusterBlocks = map[string]bool{
+	"controlPlanePlacement":   true,
+	"kubernetesNetworkConfig": true,
+	"outpostConfig":           true,
+	"provider":                true,
+}
+
+// convertClusterBlocks converts the encryption, Kubernetes network and
+// outpost configurations of the given community Cluster into the
+// corresponding blocks of an official Cluster.
+func convertClusterBlocks(source *srcv1beta1.Cluster) (*targetv1beta1.Cluster, error) {
+	paved := fieldpath.Pave(migration.ToSanitizedUnstructured(source).Object)
+	forProvider := map[string]any{}
+	for _, f := range []string{"encryptionConfig", "kubernetesNetworkConfig", "outpostConfig"} {
+		v, err := paved.GetValue("spec.forProvider." + f)
+		if err != nil {
+			if fieldpath.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get the %s of the source Cluster", f)
+		}
+		forProvider[f] = toBlocks(f, v)
+	}
+	target := &targetv1beta1.Cluster{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]any{
+		"spec": map[string]any{
+			"forProvider": forProvider,
+		},
+	}, target)
+	return target, errors.Wrap(err, "failed to convert the Cluster configuration blocks")
+}
+
+// toBlocks converts the objects of the given field, and of the nested
+// fields, that are blocks in the official Cluster into single element lists.
+func toBlocks(field string, v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			m[k] = toBlocks(k, e)
+		}
+		if clusterBlocks[field] {
+			return []any{m}
+		}
+		return m
+	case []any:
+		l := make([]any, 0, len(t))
+		for _, e := range t {
+			// the items of a list are not blocks themselves
+			l = append(l, toBlocks("", e))
+		}
+		return l
+	default:
+		return v
+	}
+}
+
+// clusterFieldNames describes the conversion of the patches of the
+// community Cluster fields into the patches of the official Cluster fields.
+var clusterFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"resourcesVpcConfig": "vpcConfig",
+	},
+	Blocks: map[string]bool{
+		"kubernetesNetworkConfig": true,
+		"outpostConfig":           true,
+		"vpcConfig":               true,
+	},
+	Paths: map[string]string{
+		"encryptionConfig[*].provider":        "encryptionConfig[*].provider[0]",
+		"outpostConfig.controlPlanePlacement": "outpostConfig[0].controlPlanePlacement[0]",
+	},
+	TagsMap: true,
+}
+
+// ClusterComposition converts the patches of a composed community Cluster.
+func ClusterComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	patchesToAdd, err := clusterFieldNames.ConvertPatches(sourceTemplate)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the Cluster patches")
+	}
+	return common.SplittedResourcePatches(convertedTemplates, targetv1beta1.Cluster_Kind, patchesToAdd)
+}
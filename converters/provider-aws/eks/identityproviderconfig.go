@@ -0,0 +1,122 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eks
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/eks/manualv1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/eks/v1beta1"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+const (
+	fieldExternalName               = "metadata.annotations[crossplane.io/external-name]"
+	fieldIdentityProviderConfigName = "spec.forProvider.oidc[0].identityProviderConfigName"
+)
+
+// identityProviderConfigFieldNames describes the conversion of the
+// community IdentityProviderConfig fields into the official
+// IdentityProviderConfig fields.
+var identityProviderConfigFieldNames = providerawscommon.FieldNames{
+	Blocks: map[string]bool{
+		"oidc": true,
+	},
+	Items: map[string]map[string]string{
+		"oidc": {
+			"clientID":  "clientId",
+			"issuerURL": "issuerUrl",
+		},
+	},
+	TagsMap: true,
+}
+
+func IdentityProviderConfigResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.IdentityProviderConfig)
+	target := &targetv1beta1.IdentityProviderConfig{}
+	if err := identityProviderConfigFieldNames.CopyInto(source, target, targetv1beta1.IdentityProviderConfig_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	// the name of the config is part of the oidc block in the official
+	// resource while it's the external name of the community resource
+	name := meta.GetExternalName(source)
+	if name == "" {
+		name = source.GetName()
+	}
+	if name != "" && len(target.Spec.ForProvider.Oidc) > 0 {
+		target.Spec.ForProvider.Oidc[0].IdentityProviderConfigName = common.PtrFromString(name)
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
+
+// IdentityProviderConfigComposition converts the patches of a composed
+// community IdentityProviderConfig. The name of the config is the external
+// name of the community resource, so the patches of the external name are
+// also converted into the patches of the oidc config name. If neither the
+// base nor the patches name the config, the config is named after the
+// composite resource.
+func IdentityProviderConfigComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	if err := identityProviderConfigFieldNames.CompositionConverter()(sourceTemplate, convertedTemplates...); err != nil {
+		return errors.Wrap(err, "failed to convert the IdentityProviderConfig patches")
+	}
+	var patchesToAdd []v1.Patch
+	for _, p := range sourceTemplate.Patches {
+		switch p.Type { //nolint:exhaustive
+		case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite, "":
+			if p.ToFieldPath == nil || *p.ToFieldPath != fieldExternalName {
+				continue
+			}
+			p.ToFieldPath = common.PtrFromString(fieldIdentityProviderConfigName)
+			patchesToAdd = append(patchesToAdd, p)
+		}
+	}
+	for i := range convertedTemplates {
+		named, err := hasIdentityProviderConfigName(convertedTemplates[i])
+		if err != nil {
+			return err
+		}
+		switch {
+		case len(patchesToAdd) > 0:
+			convertedTemplates[i].Patches = append(convertedTemplates[i].Patches, patchesToAdd...)
+		case !named:
+			common.Warnf("IdentityProviderConfig in the Composition has no name and is named after the composite resource")
+			convertedTemplates[i].Patches = append(convertedTemplates[i].Patches, v1.Patch{
+				Type:          v1.PatchTypeFromCompositeFieldPath,
+				FromFieldPath: common.PtrFromString("metadata.name"),
+				ToFieldPath:   common.PtrFromString(fieldIdentityProviderConfigName),
+			})
+		}
+	}
+	return nil
+}
+
+// hasIdentityProviderConfigName returns true if the base of the given
+// template sets the name of the oidc config.
+func hasIdentityProviderConfigName(t *v1.ComposedTemplate) (bool, error) {
+	u, err := migration.FromRawExtension(t.Base)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to convert ComposedTemplate base")
+	}
+	name, err := fieldpath.Pave(u.Object).GetString(fieldIdentityProviderConfigName)
+	return err == nil && name != "", nil
+}
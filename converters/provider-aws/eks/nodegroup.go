@@ -19,25 +19,29 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/upjet/pkg/migration"
 	"github.com/pkg/errors"
-	"github.com/upbound/extensions-migration/converters/common"
 	targetv1beta1 "github.com/upbound/provider-aws/apis/eks/v1beta1"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
 )
 
 func NodegroupResource(mg resource.Managed) ([]resource.Managed, error) {
 	source := mg.(*srcv1alpha1.NodeGroup)
 	target := &targetv1beta1.NodeGroup{}
-	if _, err := migration.CopyInto(source, target, targetv1beta1.NodeGroup_GroupVersionKind, "spec.forProvider.scalingConfig"); err != nil {
+	if _, err := migration.CopyInto(source, target, targetv1beta1.NodeGroup_GroupVersionKind, "spec.forProvider.scalingConfig",
+		"spec.forProvider.launchTemplate", "spec.forProvider.remoteAccess", "spec.forProvider.updateConfig", "spec.forProvider.labels"); err != nil {
 		return nil, errors.Wrap(err, "failed to copy source into target")
 	}
 
-	scalingConfig := &targetv1beta1.ScalingConfigParameters{
-		DesiredSize: common.PtrFloat64FromInt32(source.Spec.ForProvider.ScalingConfig.DesiredSize),
-		MinSize:     common.PtrFloat64FromInt32(source.Spec.ForProvider.ScalingConfig.MinSize),
-		MaxSize:     common.PtrFloat64FromInt32(source.Spec.ForProvider.ScalingConfig.MaxSize),
+	if source.Spec.ForProvider.ScalingConfig != nil {
+		scalingConfig := &targetv1beta1.ScalingConfigParameters{
+			DesiredSize: common.PtrFloat64FromInt32(source.Spec.ForProvider.ScalingConfig.DesiredSize),
+			MinSize:     common.PtrFloat64FromInt32(source.Spec.ForProvider.ScalingConfig.MinSize),
+			MaxSize:     common.PtrFloat64FromInt32(source.Spec.ForProvider.ScalingConfig.MaxSize),
+		}
+		target.Spec.ForProvider.ScalingConfig = append(target.Spec.ForProvider.ScalingConfig, *scalingConfig)
 	}
 
-	target.Spec.ForProvider.ScalingConfig = append(target.Spec.ForProvider.ScalingConfig, *scalingConfig)
-
 	if len(source.Spec.ForProvider.NodeRole) > 0 {
 		target.Spec.ForProvider.NodeRoleArn = &source.Spec.ForProvider.NodeRole
 	}
@@ -46,7 +50,7 @@ func NodegroupResource(mg resource.Managed) ([]resource.Managed, error) {
 	target.Spec.ForProvider.NodeRoleArnRef = source.Spec.ForProvider.NodeRoleRef
 
 	for _, subnet := range source.Spec.ForProvider.Subnets {
-		target.Spec.ForProvider.SubnetIds = append(target.Spec.ForProvider.SubnetIds, &subnet)
+		target.Spec.ForProvider.SubnetIds = append(target.Spec.ForProvider.SubnetIds, common.PtrFromString(subnet))
 	}
 
 	target.Spec.ForProvider.SubnetIDRefs = source.Spec.ForProvider.SubnetRefs
@@ -61,7 +65,70 @@ func NodegroupResource(mg resource.Managed) ([]resource.Managed, error) {
 		target.Spec.ForProvider.Taint = append(target.Spec.ForProvider.Taint, *taint)
 	}
 
+	if len(source.Spec.ForProvider.Labels) > 0 {
+		target.Spec.ForProvider.Labels = make(map[string]*string, len(source.Spec.ForProvider.Labels))
+		for k, v := range source.Spec.ForProvider.Labels {
+			target.Spec.ForProvider.Labels[k] = common.PtrFromString(v)
+		}
+	}
+
+	if lt := source.Spec.ForProvider.LaunchTemplate; lt != nil {
+		target.Spec.ForProvider.LaunchTemplate = []targetv1beta1.LaunchTemplateParameters{{
+			ID:      lt.ID,
+			Name:    lt.Name,
+			Version: lt.Version,
+		}}
+	}
+
+	if ra := source.Spec.ForProvider.RemoteAccess; ra != nil {
+		remoteAccess := targetv1beta1.RemoteAccessParameters{
+			EC2SSHKey:                     ra.EC2SSHKey,
+			SourceSecurityGroupIDRefs:     ra.SourceSecurityGroupRefs,
+			SourceSecurityGroupIDSelector: ra.SourceSecurityGroupSelector,
+		}
+		for _, sg := range ra.SourceSecurityGroups {
+			remoteAccess.SourceSecurityGroupIds = append(remoteAccess.SourceSecurityGroupIds, common.PtrFromString(sg))
+		}
+		target.Spec.ForProvider.RemoteAccess = []targetv1beta1.RemoteAccessParameters{remoteAccess}
+	}
+
+	if uc := source.Spec.ForProvider.UpdateConfig; uc != nil {
+		target.Spec.ForProvider.UpdateConfig = []targetv1beta1.UpdateConfigParameters{{
+			MaxUnavailable:           common.PtrFloat64FromInt32(uc.MaxUnavailable),
+			MaxUnavailablePercentage: common.PtrFloat64FromInt32(uc.MaxUnavailablePercentage),
+		}}
+	}
+
 	return []resource.Managed{
 		target,
 	}, nil
 }
+
+// NodeGroupFieldNames describes the conversion of the patches of the
+// community NodeGroup fields into the patches of the official NodeGroup
+// fields.
+var NodeGroupFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"nodeRole":         "nodeRoleArn",
+		"nodeRoleRef":      "nodeRoleArnRef",
+		"nodeRoleSelector": "nodeRoleArnSelector",
+		"subnets":          "subnetIds",
+		"subnetRefs":       "subnetIdRefs",
+		"subnetSelector":   "subnetIdSelector",
+		"taints":           "taint",
+	},
+	Blocks: map[string]bool{
+		"launchTemplate": true,
+		"remoteAccess":   true,
+		"scalingConfig":  true,
+		"updateConfig":   true,
+	},
+	Items: map[string]map[string]string{
+		"remoteAccess": {
+			"sourceSecurityGroups":        "sourceSecurityGroupIds",
+			"sourceSecurityGroupRefs":     "sourceSecurityGroupIdRefs",
+			"sourceSecurityGroupSelector": "sourceSecurityGroupIdSelector",
+		},
+	},
+	TagsMap: true,
+}
@@ -0,0 +1,320 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elbv2
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/elbv2/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/elbv2/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+const fieldCertificateArn = "spec.forProvider.certificateArn"
+
+// listenerFieldNames describes the conversion of the community Listener
+// fields into the official LBListener fields. The certificates of the
+// community Listener are converted separately.
+var listenerFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"defaultActions":          "defaultAction",
+		"loadBalancerARN":         "loadBalancerArn",
+		"loadBalancerARNRef":      "loadBalancerArnRef",
+		"loadBalancerARNSelector": "loadBalancerArnSelector",
+	},
+	Items: map[string]map[string]string{
+		"defaultAction": {
+			"targetGroupARN":            "targetGroupArn",
+			"targetGroupARNRef":         "targetGroupArnRef",
+			"targetGroupARNSelector":    "targetGroupArnSelector",
+			"authenticateCognitoConfig": "authenticateCognito",
+			"authenticateOIDCConfig":    "authenticateOidc",
+			"fixedResponseConfig":       "fixedResponse",
+			"forwardConfig":             "forward",
+			"redirectConfig":            "redirect",
+		},
+	},
+	Paths: map[string]string{
+		"alpnPolicy[0]": "alpnPolicy",
+	},
+	Transform: convertListener,
+}
+
+// actionBlocks maps the action configurations that are blocks in the
+// official LBListener to the renames of their fields.
+var actionBlocks = map[string]map[string]string{
+	"authenticateCognito": {
+		"userPoolARN":      "userPoolArn",
+		"userPoolClientID": "userPoolClientId",
+	},
+	"authenticateOidc": {
+		"clientID": "clientId",
+	},
+	"fixedResponse": nil,
+	"redirect":      nil,
+}
+
+var certificatePathRegexp = regexp.MustCompile(`^spec\.forProvider\.certificates\[(\d+)\]\.certificateARN$`)
+
+// convertListener converts the ALPN policy and the default actions of the
+// community Listener.
+func convertListener(forProvider map[string]any) {
+	if l, ok := forProvider["alpnPolicy"].([]any); ok {
+		delete(forProvider, "alpnPolicy")
+		if len(l) > 0 {
+			forProvider["alpnPolicy"] = l[0]
+		}
+	}
+	actions, _ := forProvider["defaultAction"].([]any)
+	for i, a := range actions {
+		am, ok := a.(map[string]any)
+		if !ok {
+			continue
+		}
+		for b, renames := range actionBlocks {
+			bm, ok := am[b].(map[string]any)
+			if !ok {
+				continue
+			}
+			for s, t := range renames {
+				if v, ok := bm[s]; ok {
+					delete(bm, s)
+					bm[t] = v
+				}
+			}
+			am[b] = []any{bm}
+		}
+		if oidc, ok := firstItem(am["authenticateOidc"]); ok {
+			if _, ok := oidc["clientSecret"]; ok {
+				delete(oidc, "clientSecret")
+				common.Warnf("Listener default action at index %d: the OIDC client secret cannot be converted, clientSecretSecretRef must be set", i)
+			}
+		}
+		if f, ok := am["forward"].(map[string]any); ok {
+			am["forward"] = []any{convertForward(i, f)}
+		}
+	}
+}
+
+// convertForward converts the forward configuration of the community
+// default action at the given index into the forward block of the official
+// default action.
+func convertForward(i int, f map[string]any) map[string]any {
+	forward := make(map[string]any)
+	if s, ok := f["targetGroupStickinessConfig"].(map[string]any); ok {
+		stickiness := make(map[string]any)
+		if v, ok := s["durationSeconds"]; ok {
+			stickiness["duration"] = v
+		}
+		if v, ok := s["enabled"]; ok {
+			stickiness["enabled"] = v
+		}
+		forward["stickiness"] = []any{stickiness}
+	}
+	tgs, _ := f["targetGroups"].([]any)
+	targetGroups := make([]any, 0, len(tgs))
+	for j, tg := range tgs {
+		tgm, ok := tg.(map[string]any)
+		if !ok {
+			continue
+		}
+		if tgm["targetGroupARN"] == nil {
+			common.Warnf("Listener default action at index %d: the forwarded target group at index %d has no ARN and is dropped, references cannot be converted", i, j)
+			continue
+		}
+		t := map[string]any{
+			"arn": tgm["targetGroupARN"],
+		}
+		if w, ok := tgm["weight"]; ok {
+			t["weight"] = w
+		}
+		targetGroups = append(targetGroups, t)
+	}
+	if len(targetGroups) > 0 {
+		forward["targetGroup"] = targetGroups
+	}
+	return forward
+}
+
+func firstItem(v any) (map[string]any, bool) {
+	l, ok := v.([]any)
+	if !ok || len(l) == 0 {
+		return nil, false
+	}
+	m, ok := l[0].(map[string]any)
+	return m, ok
+}
+
+// certificateOrder returns the indices of the given certificates with the
+// default certificate first. The first certificate becomes the certificate
+// of the official LBListener, and the rest become LBListenerCertificates.
+// The certificates without ARNs are kept, as their ARNs may be patched in
+// compositions.
+func certificateOrder(certs []*srcv1alpha1.Certificate) []int {
+	order := make([]int, 0, len(certs))
+	for i, c := range certs {
+		if c == nil {
+			continue
+		}
+		if c.IsDefault != nil && *c.IsDefault {
+			order = append([]int{i}, order...)
+			continue
+		}
+		order = append(order, i)
+	}
+	return order
+}
+
+func ListenerResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.Listener)
+	target := &targetv1beta1.LBListener{}
+	if err := listenerFieldNames.CopyInto(source, target, targetv1beta1.LBListener_GroupVersionKind, "spec.forProvider.certificates"); err != nil {
+		return nil, err
+	}
+	listenerMRs := []resource.Managed{target}
+	certs := source.Spec.ForProvider.Certificates
+	for i, c := range certificateOrder(certs) {
+		if i == 0 {
+			target.Spec.ForProvider.CertificateArn = certs[c].CertificateARN
+			continue
+		}
+		lc, err := newListenerCertificate(source, i, certs[c].CertificateARN)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the certificate at index %d", c)
+		}
+		listenerMRs = append(listenerMRs, lc)
+	}
+	return listenerMRs, nil
+}
+
+// newListenerCertificate returns the official LBListenerCertificate of the
+// additional certificate with the given ARN of the community Listener.
+func newListenerCertificate(source *srcv1alpha1.Listener, i int, certificateARN *string) (*targetv1beta1.LBListenerCertificate, error) {
+	forProvider := map[string]any{
+		"region": source.Spec.ForProvider.Region,
+		"listenerArnRef": map[string]any{
+			"name": source.GetName(),
+		},
+		"listenerArnSelector": map[string]any{
+			"matchControllerRef": true,
+		},
+	}
+	if certificateARN != nil {
+		forProvider["certificateArn"] = *certificateARN
+	}
+	externalName := meta.GetExternalName(source)
+	if len(externalName) > 0 {
+		forProvider["listenerArn"] = externalName
+	}
+	lc := &targetv1beta1.LBListenerCertificate{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]any{
+		"spec": map[string]any{
+			"forProvider": forProvider,
+		},
+	}, lc); err != nil {
+		return nil, errors.Wrap(err, "failed to convert into LBListenerCertificate")
+	}
+	lc.SetGroupVersionKind(targetv1beta1.LBListenerCertificate_GroupVersionKind)
+	if len(source.GetName()) > 0 {
+		lc.SetName(fmt.Sprintf("%s-certificate-%d", source.GetName(), i))
+	}
+	if len(source.Labels) > 0 {
+		lc.Labels = make(map[string]string, len(source.Labels)+1)
+		for k, v := range source.Labels {
+			lc.Labels[k] = v
+		}
+		lc.Labels["resourceType"] = targetv1beta1.LBListenerCertificate_Kind
+	}
+	// the external name of an LBListenerCertificate is <listener ARN>_<certificate ARN>
+	if len(externalName) > 0 && certificateARN != nil {
+		meta.SetExternalName(lc, fmt.Sprintf("%s_%s", externalName, *certificateARN))
+	}
+	lc.Spec.DeletionPolicy = source.Spec.DeletionPolicy
+	lc.Spec.ProviderConfigReference = source.Spec.ProviderConfigReference
+	return lc, nil
+}
+
+// ListenerComposition converts the patches of a composed community
+// Listener. The patches of the default certificate are routed to the
+// LBListener and the patches of the additional certificates to their
+// LBListenerCertificates.
+func ListenerComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	listenerPatches, err := listenerFieldNames.ConvertPatches(sourceTemplate)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the Listener patches")
+	}
+	u, err := migration.FromRawExtension(sourceTemplate.Base)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the Listener base")
+	}
+	source := &srcv1alpha1.Listener{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, source); err != nil {
+		return errors.Wrap(err, "failed to convert the Listener base")
+	}
+	// position of each certificate in the converted resources
+	positions := make(map[int]int)
+	for i, c := range certificateOrder(source.Spec.ForProvider.Certificates) {
+		positions[c] = i
+	}
+	var certificateTemplates []*v1.ComposedTemplate
+	for _, t := range convertedTemplates {
+		ct, err := migration.FromRawExtension(t.Base)
+		if err != nil {
+			return errors.Wrap(err, "failed to convert ComposedTemplate base")
+		}
+		if ct.GetKind() == targetv1beta1.LBListenerCertificate_Kind {
+			certificateTemplates = append(certificateTemplates, t)
+		}
+	}
+	for _, p := range sourceTemplate.Patches {
+		switch p.Type { //nolint:exhaustive
+		case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite, "":
+		default:
+			continue
+		}
+		if p.ToFieldPath == nil {
+			continue
+		}
+		m := certificatePathRegexp.FindStringSubmatch(*p.ToFieldPath)
+		if m == nil {
+			continue
+		}
+		i, _ := strconv.Atoi(m[1])
+		pos, ok := positions[i]
+		if !ok {
+			continue
+		}
+		to := fieldCertificateArn
+		p.ToFieldPath = &to
+		if pos == 0 {
+			listenerPatches = append(listenerPatches, p)
+			continue
+		}
+		if pos-1 < len(certificateTemplates) {
+			certificateTemplates[pos-1].Patches = append(certificateTemplates[pos-1].Patches, p)
+		}
+	}
+	return common.SplittedResourcePatches(convertedTemplates, targetv1beta1.LBListener_Kind, listenerPatches)
+}
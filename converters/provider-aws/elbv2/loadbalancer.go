@@ -0,0 +1,144 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elbv2
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/elbv2/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/elbv2/v1beta1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+const (
+	schemeInternal       = "internal"
+	schemeInternetFacing = "internet-facing"
+
+	fieldScheme   = "spec.forProvider.scheme"
+	fieldInternal = "spec.forProvider.internal"
+)
+
+// schemeToInternal maps the community LoadBalancer schemes to the internal
+// flag of the official LB, and internalToScheme is its inverse.
+var (
+	schemeToInternal = map[string]extv1.JSON{
+		schemeInternal:       {Raw: []byte("true")},
+		schemeInternetFacing: {Raw: []byte("false")},
+	}
+	internalToScheme = map[string]extv1.JSON{
+		"true":  {Raw: []byte(`"` + schemeInternal + `"`)},
+		"false": {Raw: []byte(`"` + schemeInternetFacing + `"`)},
+	}
+)
+
+// loadBalancerFieldNames describes the conversion of the community
+// LoadBalancer fields into the official LB fields.
+var loadBalancerFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"customerOwnedIPv4Pool": "customerOwnedIpv4Pool",
+		"subnetMappings":        "subnetMapping",
+		"type":                  "loadBalancerType",
+	},
+	Items: map[string]map[string]string{
+		"subnetMapping": {
+			"allocationID":       "allocationId",
+			"iPv6Address":        "ipv6Address",
+			"privateIPv4Address": "privateIpv4Address",
+			"subnetID":           "subnetId",
+		},
+	},
+	Transform: convertScheme,
+}
+
+// convertScheme converts the scheme of the community LoadBalancer into the
+// internal flag of the official LB.
+func convertScheme(forProvider map[string]any) {
+	scheme, ok := forProvider["scheme"].(string)
+	if !ok {
+		return
+	}
+	delete(forProvider, "scheme")
+	forProvider["internal"] = scheme == schemeInternal
+}
+
+func LoadBalancerResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.LoadBalancer)
+	target := &targetv1beta1.LB{}
+	if err := loadBalancerFieldNames.CopyInto(source, target, targetv1beta1.LB_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
+
+// LoadBalancerComposition converts the patches of a composed community
+// LoadBalancer. The patches of the scheme are converted into patches of the
+// internal flag with a map transform.
+func LoadBalancerComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	if err := loadBalancerFieldNames.CompositionConverter()(sourceTemplate, convertedTemplates...); err != nil {
+		return errors.Wrap(err, "failed to convert the LoadBalancer patches")
+	}
+	patchesToAdd := schemePatches(sourceTemplate)
+	for i := range convertedTemplates {
+		convertedTemplates[i].Patches = append(convertedTemplates[i].Patches, patchesToAdd...)
+	}
+	return nil
+}
+
+// schemePatches converts the patches of the scheme of the given source
+// template into patches of the internal flag.
+func schemePatches(sourceTemplate v1.ComposedTemplate) []v1.Patch {
+	var patches []v1.Patch
+	for _, p := range sourceTemplate.Patches {
+		transforms := make([]v1.Transform, len(p.Transforms), len(p.Transforms)+2)
+		copy(transforms, p.Transforms)
+		switch p.Type { //nolint:exhaustive
+		case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite, "":
+			if p.ToFieldPath == nil || *p.ToFieldPath != fieldScheme {
+				continue
+			}
+			p.ToFieldPath = common.PtrFromString(fieldInternal)
+			p.Transforms = append(transforms, v1.Transform{
+				Type: v1.TransformTypeMap,
+				Map:  &v1.MapTransform{Pairs: schemeToInternal},
+			})
+		case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeCombineToComposite:
+			if p.FromFieldPath == nil || *p.FromFieldPath != fieldScheme {
+				continue
+			}
+			p.FromFieldPath = common.PtrFromString(fieldInternal)
+			// the map transform requires a string input
+			p.Transforms = append([]v1.Transform{
+				{
+					Type:    v1.TransformTypeConvert,
+					Convert: &v1.ConvertTransform{ToType: v1.TransformIOTypeString},
+				},
+				{
+					Type: v1.TransformTypeMap,
+					Map:  &v1.MapTransform{Pairs: internalToScheme},
+				},
+			}, transforms...)
+		default:
+			continue
+		}
+		patches = append(patches, p)
+	}
+	return patches
+}
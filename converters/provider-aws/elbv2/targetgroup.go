@@ -0,0 +1,97 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elbv2
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/elbv2/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/elbv2/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// healthCheckFields maps the health check fields of the community
+// TargetGroup to the fields of the health check block of the official
+// LBTargetGroup.
+var healthCheckFields = map[string]string{
+	"healthCheckEnabled":         "enabled",
+	"healthCheckIntervalSeconds": "interval",
+	"healthCheckPath":            "path",
+	"healthCheckPort":            "port",
+	"healthCheckProtocol":        "protocol",
+	"healthCheckTimeoutSeconds":  "timeout",
+	"healthyThresholdCount":      "healthyThreshold",
+	"unhealthyThresholdCount":    "unhealthyThreshold",
+}
+
+// TargetGroupFieldNames describes the conversion of the community
+// TargetGroup fields into the official LBTargetGroup fields.
+var TargetGroupFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"vpcID":         "vpcId",
+		"vpcIDRef":      "vpcIdRef",
+		"vpcIDSelector": "vpcIdSelector",
+	},
+	Paths:     healthCheckPaths(),
+	Transform: convertHealthCheck,
+}
+
+// healthCheckPaths returns the conversions of the field paths of the
+// health check fields.
+func healthCheckPaths() map[string]string {
+	paths := map[string]string{
+		"matcher.httpCode": "healthCheck[0].matcher",
+		"matcher.grpcCode": "healthCheck[0].matcher",
+	}
+	for s, t := range healthCheckFields {
+		paths[s] = "healthCheck[0]." + t
+	}
+	return paths
+}
+
+// convertHealthCheck moves the health check fields of the community
+// TargetGroup into the health check block of the official LBTargetGroup.
+func convertHealthCheck(forProvider map[string]any) {
+	hc := make(map[string]any)
+	for s, t := range healthCheckFields {
+		if v, ok := forProvider[s]; ok {
+			delete(forProvider, s)
+			hc[t] = v
+		}
+	}
+	if m, ok := forProvider["matcher"].(map[string]any); ok {
+		delete(forProvider, "matcher")
+		for _, k := range []string{"httpCode", "grpcCode"} {
+			if c, ok := m[k].(string); ok && c != "" {
+				hc["matcher"] = c
+				break
+			}
+		}
+	}
+	if len(hc) > 0 {
+		forProvider["healthCheck"] = []any{hc}
+	}
+}
+
+func TargetGroupResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.TargetGroup)
+	target := &targetv1beta1.LBTargetGroup{}
+	if err := TargetGroupFieldNames.CopyInto(source, target, targetv1beta1.LBTargetGroup_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
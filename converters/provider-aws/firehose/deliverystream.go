@@ -0,0 +1,263 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firehose
+
+import (
+	"fmt"
+
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/firehose/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/firehose/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+const (
+	destinationExtendedS3 = "extended_s3"
+
+	fieldExtendedS3       = "extendedS3DestinationConfiguration"
+	fieldS3               = "s3DestinationConfiguration"
+	fieldExtendedS3Target = "extendedS3Configuration"
+)
+
+// unsupportedDestinations are the community destination configurations
+// that cannot be converted.
+var unsupportedDestinations = []string{
+	"elasticsearchDestinationConfiguration",
+	"httpEndpointDestinationConfiguration",
+	"redshiftDestinationConfiguration",
+	"splunkDestinationConfiguration",
+}
+
+// unsupportedS3Fields are the community extended S3 destination
+// configuration fields that cannot be converted.
+var unsupportedS3Fields = []string{
+	"dataFormatConversionConfiguration",
+}
+
+// s3DestinationFields maps the community S3 destination configuration
+// fields to the official extendedS3Configuration fields.
+var s3DestinationFields = map[string]string{
+	"bucketARN":                "bucketArn",
+	"bucketARNRef":             "bucketArnRef",
+	"bucketARNSelector":        "bucketArnSelector",
+	"cloudWatchLoggingOptions": "cloudwatchLoggingOptions",
+	"compressionFormat":        "compressionFormat",
+	"errorOutputPrefix":        "errorOutputPrefix",
+	"prefix":                   "prefix",
+	"roleARN":                  "roleArn",
+	"roleARNRef":               "roleArnRef",
+	"roleARNSelector":          "roleArnSelector",
+	"s3BackupMode":             "s3BackupMode",
+}
+
+// DeliveryStreamFieldNames describes the conversion of the community
+// DeliveryStream fields into the official DeliveryStream fields.
+var DeliveryStreamFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"deliveryStreamName":                         "name",
+		"deliveryStreamEncryptionConfigurationInput": "serverSideEncryption",
+		"kinesisStreamSourceConfiguration":           "kinesisSourceConfiguration",
+	},
+	Blocks: map[string]bool{
+		"serverSideEncryption":       true,
+		"kinesisSourceConfiguration": true,
+	},
+	Items: map[string]map[string]string{
+		"serverSideEncryption": {
+			"keyARN": "keyArn",
+		},
+		"kinesisSourceConfiguration": {
+			"kinesisStreamARN": "kinesisStreamArn",
+			"roleARN":          "roleArn",
+		},
+	},
+	Paths: deliveryStreamPaths(),
+	Transform: func(forProvider map[string]any) {
+		convertServerSideEncryption(forProvider)
+		convertS3Destination(forProvider)
+	},
+}
+
+// deliveryStreamPaths returns the conversions of the patches of the S3
+// destination configuration fields.
+func deliveryStreamPaths() map[string]string {
+	paths := map[string]string{}
+	for _, s := range []string{fieldExtendedS3, fieldS3} {
+		for f, t := range s3DestinationFields {
+			paths[s+"."+f] = fieldExtendedS3Target + "[0]." + t
+		}
+		paths[s+".bufferingHints.intervalInSeconds"] = fieldExtendedS3Target + "[0].bufferingInterval"
+		paths[s+".bufferingHints.sizeInMBs"] = fieldExtendedS3Target + "[0].bufferingSize"
+		for _, f := range []string{"enabled", "logGroupName", "logStreamName"} {
+			paths[s+".cloudWatchLoggingOptions."+f] = fieldExtendedS3Target + "[0].cloudwatchLoggingOptions[0]." + f
+		}
+		paths[s+".encryptionConfiguration.kmsEncryptionConfig.awsKMSKeyARN"] = fieldExtendedS3Target + "[0].kmsKeyArn"
+	}
+	return paths
+}
+
+// convertServerSideEncryption enables the server-side encryption if its
+// configuration is specified for the community DeliveryStream.
+func convertServerSideEncryption(forProvider map[string]any) {
+	l, ok := forProvider["serverSideEncryption"].([]any)
+	if !ok || len(l) == 0 {
+		return
+	}
+	if sse, ok := l[0].(map[string]any); ok {
+		sse["enabled"] = true
+	}
+}
+
+// convertS3Destination converts the S3 or the extended S3 destination
+// configuration of the community DeliveryStream into the official
+// extendedS3Configuration. The other destinations are rejected by
+// checkDestination.
+func convertS3Destination(forProvider map[string]any) {
+	var s3 map[string]any
+	for _, f := range []string{fieldExtendedS3, fieldS3} {
+		if m, ok := forProvider[f].(map[string]any); ok && s3 == nil {
+			s3 = m
+		}
+		delete(forProvider, f)
+	}
+	if s3 == nil {
+		return
+	}
+	converted := convertS3Configuration(s3)
+	if pc, ok := s3["processingConfiguration"].(map[string]any); ok {
+		converted["processingConfiguration"] = []any{convertProcessingConfiguration(pc)}
+	}
+	if dp, ok := s3["dynamicPartitioningConfiguration"].(map[string]any); ok {
+		c := map[string]any{}
+		if v, ok := dp["enabled"]; ok {
+			c["enabled"] = v
+		}
+		if ro, ok := dp["retryOptions"].(map[string]any); ok {
+			if v, ok := ro["durationInSeconds"]; ok {
+				c["retryDuration"] = v
+			}
+		}
+		converted["dynamicPartitioningConfiguration"] = []any{c}
+	}
+	if bc, ok := s3["s3BackupConfiguration"].(map[string]any); ok {
+		converted["s3BackupConfiguration"] = []any{convertS3Configuration(bc)}
+	}
+	forProvider[fieldExtendedS3Target] = []any{converted}
+	forProvider["destination"] = destinationExtendedS3
+}
+
+// convertS3Configuration converts the given community S3 destination
+// configuration into its official representation.
+func convertS3Configuration(s3 map[string]any) map[string]any {
+	converted := make(map[string]any, len(s3))
+	for f, t := range s3DestinationFields {
+		if v, ok := s3[f]; ok {
+			converted[t] = v
+		}
+	}
+	if o, ok := converted["cloudwatchLoggingOptions"].(map[string]any); ok {
+		converted["cloudwatchLoggingOptions"] = []any{o}
+	}
+	if bh, ok := s3["bufferingHints"].(map[string]any); ok {
+		if v, ok := bh["intervalInSeconds"]; ok {
+			converted["bufferingInterval"] = v
+		}
+		if v, ok := bh["sizeInMBs"]; ok {
+			converted["bufferingSize"] = v
+		}
+	}
+	if ec, ok := s3["encryptionConfiguration"].(map[string]any); ok {
+		if kms, ok := ec["kmsEncryptionConfig"].(map[string]any); ok {
+			if v, ok := kms["awsKMSKeyARN"]; ok {
+				converted["kmsKeyArn"] = v
+			}
+		}
+	}
+	return converted
+}
+
+// convertProcessingConfiguration converts the given community processing
+// configuration, e.g., of the Lambda transforms, into its official
+// representation.
+func convertProcessingConfiguration(pc map[string]any) map[string]any {
+	converted := map[string]any{}
+	if v, ok := pc["enabled"]; ok {
+		converted["enabled"] = v
+	}
+	l, ok := pc["processors"].([]any)
+	if !ok {
+		return converted
+	}
+	processors := make([]any, 0, len(l))
+	for _, p := range l {
+		pm, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		processor := map[string]any{}
+		for _, k := range []string{"type", "type_"} {
+			if v, ok := pm[k]; ok {
+				processor["type"] = v
+			}
+		}
+		if params, ok := pm["parameters"]; ok {
+			processor["parameters"] = params
+		}
+		processors = append(processors, processor)
+	}
+	converted["processors"] = processors
+	return converted
+}
+
+// checkDestination returns an error if the given community DeliveryStream
+// has a destination configuration that cannot be converted.
+func checkDestination(source *srcv1alpha1.DeliveryStream) error {
+	pv := fieldpath.Pave(migration.ToSanitizedUnstructured(source).Object)
+	for _, d := range unsupportedDestinations {
+		if v, err := pv.GetValue("spec.forProvider." + d); err == nil && v != nil {
+			return errors.Errorf("DeliveryStream %q: the destination %s cannot be converted, only the S3 destinations are supported", source.GetName(), d)
+		}
+	}
+	for _, f := range unsupportedS3Fields {
+		if v, err := pv.GetValue(fmt.Sprintf("spec.forProvider.%s.%s", fieldExtendedS3, f)); err == nil && v != nil {
+			return errors.Errorf("DeliveryStream %q: the %s of the extended S3 destination cannot be converted", source.GetName(), f)
+		}
+	}
+	return nil
+}
+
+func DeliveryStreamResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.DeliveryStream)
+	if err := checkDestination(source); err != nil {
+		return nil, err
+	}
+	target := &targetv1beta1.DeliveryStream{}
+	if err := DeliveryStreamFieldNames.CopyInto(source, target, targetv1beta1.DeliveryStream_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	// the name of the delivery stream is required by the official
+	// DeliveryStream
+	if name := meta.GetExternalName(source); target.Spec.ForProvider.Name == nil && len(name) > 0 {
+		target.Spec.ForProvider.Name = &name
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
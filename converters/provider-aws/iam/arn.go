@@ -0,0 +1,90 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iam
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	arnPrefix          = "arn"
+	arnServiceIAM      = "iam"
+	resourceTypeRole   = "role"
+	resourceTypePolicy = "policy"
+	// accountAWS is the account of the AWS managed policies, e.g.,
+	// arn:aws:iam::aws:policy/AdministratorAccess
+	accountAWS = "aws"
+)
+
+var accountIDRegexp = regexp.MustCompile(`^\d{12}$`)
+
+// iamARN is a parsed IAM ARN of the form
+// arn:<partition>:iam::<account>:<resource type><path><name>
+type iamARN struct {
+	Partition    string
+	Account      string
+	ResourceType string
+	// Path is the path of the resource including its leading and trailing
+	// slashes, e.g., /team/a/ or / if the resource has no path.
+	Path string
+	Name string
+}
+
+// isARN returns true if the given string looks like an ARN.
+func isARN(s string) bool {
+	return strings.HasPrefix(s, arnPrefix+":")
+}
+
+// parseIAMARN parses the given IAM ARN. The resource type of the ARN must
+// be the given one.
+func parseIAMARN(s, resourceType string) (iamARN, error) {
+	parts := strings.SplitN(s, ":", 6)
+	if len(parts) != 6 || parts[0] != arnPrefix {
+		return iamARN{}, errors.Errorf("%q is not an ARN", s)
+	}
+	a := iamARN{
+		Partition: parts[1],
+		Account:   parts[4],
+	}
+	switch {
+	case a.Partition == "":
+		return iamARN{}, errors.Errorf("ARN %q has no partition", s)
+	case parts[2] != arnServiceIAM:
+		return iamARN{}, errors.Errorf("ARN %q is not an IAM ARN", s)
+	case parts[3] != "":
+		return iamARN{}, errors.Errorf("IAM ARN %q must not have a region", s)
+	case !accountIDRegexp.MatchString(a.Account) && !(resourceType == resourceTypePolicy && a.Account == accountAWS):
+		return iamARN{}, errors.Errorf("IAM ARN %q has an invalid account %q", s, a.Account)
+	}
+	resource := parts[5]
+	i := strings.Index(resource, "/")
+	if i < 0 {
+		return iamARN{}, errors.Errorf("IAM ARN %q has no resource name", s)
+	}
+	a.ResourceType = resource[:i]
+	if a.ResourceType != resourceType {
+		return iamARN{}, errors.Errorf("IAM ARN %q is not a %s ARN", s, resourceType)
+	}
+	j := strings.LastIndex(resource, "/")
+	a.Path = resource[i : j+1]
+	a.Name = resource[j+1:]
+	if a.Name == "" {
+		return iamARN{}, errors.Errorf("IAM ARN %q has no resource name", s)
+	}
+	return a, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iam
+
+import "testing"
+
+func TestParseIAMARN(t *testing.T) {
+	type args struct {
+		arn          string
+		resourceType string
+	}
+	type want struct {
+		arn iamARN
+		err bool
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"RoleWithPath": {
+			args: args{
+				arn:          "arn:aws:iam::123456789012:role/team/a/admin",
+				resourceType: resourceTypeRole,
+			},
+			want: want{
+				arn: iamARN{
+					Partition:    "aws",
+					Account:      "123456789012",
+					ResourceType: resourceTypeRole,
+					Path:         "/team/a/",
+					Name:         "admin",
+				},
+			},
+		},
+		"RoleWithoutPath": {
+			args: args{
+				arn:          "arn:aws-us-gov:iam::123456789012:role/admin",
+				resourceType: resourceTypeRole,
+			},
+			want: want{
+				arn: iamARN{
+					Partition:    "aws-us-gov",
+					Account:      "123456789012",
+					ResourceType: resourceTypeRole,
+					Path:         "/",
+					Name:         "admin",
+				},
+			},
+		},
+		"AWSManagedPolicy": {
+			args: args{
+				arn:          "arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole",
+				resourceType: resourceTypePolicy,
+			},
+			want: want{
+				arn: iamARN{
+					Partition:    "aws",
+					Account:      accountAWS,
+					ResourceType: resourceTypePolicy,
+					Path:         "/service-role/",
+					Name:         "AWSLambdaBasicExecutionRole",
+				},
+			},
+		},
+		"AWSAccountRole": {
+			args: args{
+				arn:          "arn:aws:iam::aws:role/admin",
+				resourceType: resourceTypeRole,
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"NameWithoutSlash": {
+			args: args{
+				arn:          "arn:aws:iam::123456789012:admin",
+				resourceType: resourceTypeRole,
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"NoName": {
+			args: args{
+				arn:          "arn:aws:iam::123456789012:role/team/",
+				resourceType: resourceTypeRole,
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"WrongResourceType": {
+			args: args{
+				arn:          "arn:aws:iam::123456789012:policy/admin",
+				resourceType: resourceTypeRole,
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"NotIAM": {
+			args: args{
+				arn:          "arn:aws:s3:::bucket/admin",
+				resourceType: resourceTypeRole,
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"NotARN": {
+			args: args{
+				arn:          "admin",
+				resourceType: resourceTypeRole,
+			},
+			want: want{
+				err: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseIAMARN(tc.args.arn, tc.args.resourceType)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\nparseIAMARN(%q): want error %t, got %v", tc.args.arn, tc.want.err, err)
+			}
+			if got != tc.want.arn {
+				t.Errorf("\nparseIAMARN(%q): want %+v, got %+v", tc.args.arn, tc.want.arn, got)
+			}
+		})
+	}
+}
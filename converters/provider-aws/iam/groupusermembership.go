@@ -0,0 +1,83 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iam
+
+import (
+	"fmt"
+
+	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/iam/v1beta1"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/iam/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// groupUserMembershipFieldNames describes the conversion of the community
+// GroupUserMembership fields into the official UserGroupMembership fields.
+var groupUserMembershipFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"userName":         "user",
+		"userNameRef":      "userRef",
+		"userNameSelector": "userSelector",
+	},
+}
+
+// groupUserMembershipFieldPaths additionally renames the field paths of the
+// group of the community resource, which becomes the single group of the
+// official resource, to the first element of the groups list.
+var groupUserMembershipFieldPaths = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"userName":          "user",
+		"userNameRef":       "userRef",
+		"userNameSelector":  "userSelector",
+		"groupName":         "groups[0]",
+		"groupNameRef":      "groupsRefs[0]",
+		"groupNameSelector": "groupsSelector",
+	},
+}
+
+func GroupUserMembershipResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1beta1.GroupUserMembership)
+	target := &targetv1beta1.UserGroupMembership{}
+	if err := groupUserMembershipFieldNames.CopyInto(source, target, targetv1beta1.UserGroupMembership_GroupVersionKind,
+		"spec.forProvider.groupName", "spec.forProvider.groupNameRef", "spec.forProvider.groupNameSelector"); err != nil {
+		return nil, err
+	}
+	p := source.Spec.ForProvider
+	if p.GroupName != "" {
+		target.Spec.ForProvider.Groups = []*string{&p.GroupName}
+	}
+	if p.GroupNameRef != nil {
+		target.Spec.ForProvider.GroupsRefs = []xpv1.Reference{*p.GroupNameRef}
+	}
+	target.Spec.ForProvider.GroupsSelector = p.GroupNameSelector
+	// the official resource is identified by the user and its groups
+	if p.UserName != "" && p.GroupName != "" {
+		meta.SetExternalName(target, fmt.Sprintf("%s/%s", p.UserName, p.GroupName))
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
+
+// GroupUserMembershipComposition converts the patches of a composed
+// community GroupUserMembership.
+func GroupUserMembershipComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	return errors.Wrap(groupUserMembershipFieldPaths.CompositionConverter()(sourceTemplate, convertedTemplates...), "failed to convert the GroupUserMembership patches")
+}
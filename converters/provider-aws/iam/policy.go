@@ -15,9 +15,8 @@
 package iam
 
 import (
-	"strings"
-
 	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/iam/v1beta1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/upjet/pkg/migration"
 	"github.com/pkg/errors"
@@ -37,10 +36,13 @@ func PolicyResource(mg resource.Managed) ([]resource.Managed, error) {
 	}
 	target.Spec.ForProvider.Policy = &source.Spec.ForProvider.Document
 
-	externalNamePolicy := source.Annotations["crossplane.io/external-name"]
-	if len(externalNamePolicy) > 0 {
-		newExternalName := strings.SplitAfter(externalNamePolicy, "/")
-		target.Annotations["crossplane.io/external-name"] = newExternalName[1]
+	// both the community and the official Policy are identified by their
+	// full ARNs, including the paths of the policies
+	if externalName := meta.GetExternalName(source); len(externalName) > 0 {
+		if _, err := parseIAMARN(externalName, resourceTypePolicy); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the external name of the Policy")
+		}
+		meta.SetExternalName(target, externalName)
 	}
 
 	return []resource.Managed{
@@ -15,28 +15,39 @@
 package iam
 
 import (
-	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/iam/v1beta1"
+	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/iam/v1beta1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
-	"github.com/crossplane/upjet/pkg/migration"
 	"github.com/pkg/errors"
 	targetv1beta1 "github.com/upbound/provider-aws/apis/iam/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
 )
 
+// RoleFieldNames describes the conversion of the community Role fields into
+// the official Role fields. The description, path, permissionsBoundary and
+// maxSessionDuration fields have the same names in both resources. The
+// inline policies of a community Role are separate RolePolicy resources
+// which are converted by RolePolicyResource.
+var RoleFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"assumeRolePolicyDocument": "assumeRolePolicy",
+	},
+}
+
 func RoleResource(mg resource.Managed) ([]resource.Managed, error) {
-	source := mg.(*srcv1alpha1.Role)
+	source := mg.(*srcv1beta1.Role)
 	target := &targetv1beta1.Role{}
-	if _, err := migration.CopyInto(source, target, targetv1beta1.Role_GroupVersionKind, "spec.forProvider.tags"); err != nil {
-		return nil, errors.Wrap(err, "failed to copy source into target")
-	}
-	target.Spec.ForProvider.Tags = make(map[string]*string, len(source.Spec.ForProvider.Tags))
-	for _, t := range source.Spec.ForProvider.Tags {
-		v := t.Value
-		target.Spec.ForProvider.Tags[t.Key] = &v
+	if err := RoleFieldNames.CopyInto(source, target, targetv1beta1.Role_GroupVersionKind); err != nil {
+		return nil, err
 	}
-	target.Spec.ForProvider.AssumeRolePolicy = &source.Spec.ForProvider.AssumeRolePolicyDocument
-	if source.Spec.ForProvider.MaxSessionDuration != nil {
-		convertMaxSessionDuration := float64(*source.Spec.ForProvider.MaxSessionDuration)
-		target.Spec.ForProvider.MaxSessionDuration = &convertMaxSessionDuration
+	// the official Role is identified by the name of the role
+	if externalName := meta.GetExternalName(source); isARN(externalName) {
+		a, err := parseIAMARN(externalName, resourceTypeRole)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse the external name of the Role")
+		}
+		meta.SetExternalName(target, a.Name)
 	}
 	return []resource.Managed{
 		target,
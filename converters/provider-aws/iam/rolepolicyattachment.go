@@ -15,7 +15,10 @@
 package iam
 
 import (
+	"fmt"
+
 	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/iam/v1beta1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/upjet/pkg/migration"
 	"github.com/pkg/errors"
@@ -31,6 +34,14 @@ func RolePolicyAttachmentResource(mg resource.Managed) ([]resource.Managed, erro
 	target.Spec.ForProvider.Role = &source.Spec.ForProvider.RoleName
 	target.Spec.ForProvider.RoleRef = source.Spec.ForProvider.RoleNameRef
 	target.Spec.ForProvider.RoleSelector = source.Spec.ForProvider.RoleNameSelector
+	// the official RolePolicyAttachment is identified by the name of the
+	// role and the ARN of the attached policy
+	if source.Spec.ForProvider.PolicyARN != "" && source.Spec.ForProvider.RoleName != "" {
+		if _, err := parseIAMARN(source.Spec.ForProvider.PolicyARN, resourceTypePolicy); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the policy ARN of the RolePolicyAttachment")
+		}
+		meta.SetExternalName(target, fmt.Sprintf("%s/%s", source.Spec.ForProvider.RoleName, source.Spec.ForProvider.PolicyARN))
+	}
 	return []resource.Managed{
 		target,
 	}, nil
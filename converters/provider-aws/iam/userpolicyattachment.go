@@ -0,0 +1,47 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iam
+
+import (
+	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/iam/v1beta1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/iam/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// UserPolicyAttachmentFieldNames describes the conversion of the community
+// UserPolicyAttachment fields into the official UserPolicyAttachment fields.
+var UserPolicyAttachmentFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"policyARN":         "policyArn",
+		"policyARNRef":      "policyArnRef",
+		"policyARNSelector": "policyArnSelector",
+		"userName":          "user",
+		"userNameRef":       "userRef",
+		"userNameSelector":  "userSelector",
+	},
+}
+
+func UserPolicyAttachmentResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1beta1.UserPolicyAttachment)
+	target := &targetv1beta1.UserPolicyAttachment{}
+	if err := UserPolicyAttachmentFieldNames.CopyInto(source, target, targetv1beta1.UserPolicyAttachment_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kinesis
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/kinesis/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/kinesis/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+const encryptionTypeKMS = "KMS"
+
+// StreamFieldNames describes the conversion of the community Stream fields
+// into the official Stream fields.
+var StreamFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"retentionPeriodHours": "retentionPeriod",
+		"kmsKeyARN":            "kmsKeyId",
+		"kmsKeyARNRef":         "kmsKeyIdRef",
+		"kmsKeyARNSelector":    "kmsKeyIdSelector",
+	},
+	Blocks: map[string]bool{
+		"streamModeDetails": true,
+	},
+	Paths: map[string]string{
+		"enhancedMetrics[0].shardLevelMetrics": "shardLevelMetrics",
+	},
+	Transform: convertStreamEncryptionAndMetrics,
+	TagsMap:   true,
+}
+
+// convertStreamEncryptionAndMetrics flattens the shard level metrics of the
+// enhanced monitoring configurations of the community Stream and sets the
+// encryption type of the streams encrypted with a KMS key.
+func convertStreamEncryptionAndMetrics(forProvider map[string]any) {
+	if l, ok := forProvider["enhancedMetrics"].([]any); ok {
+		delete(forProvider, "enhancedMetrics")
+		var metrics []any
+		for _, e := range l {
+			em, ok := e.(map[string]any)
+			if !ok {
+				continue
+			}
+			if ms, ok := em["shardLevelMetrics"].([]any); ok {
+				metrics = append(metrics, ms...)
+			}
+		}
+		if len(metrics) > 0 {
+			forProvider["shardLevelMetrics"] = metrics
+		}
+	}
+	_, key := forProvider["kmsKeyId"]
+	_, ref := forProvider["kmsKeyIdRef"]
+	_, selector := forProvider["kmsKeyIdSelector"]
+	if _, ok := forProvider["encryptionType"]; !ok && (key || ref || selector) {
+		forProvider["encryptionType"] = encryptionTypeKMS
+	}
+}
+
+func StreamResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.Stream)
+	target := &targetv1beta1.Stream{}
+	if err := StreamFieldNames.CopyInto(source, target, targetv1beta1.Stream_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
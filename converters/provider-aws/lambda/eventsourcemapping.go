@@ -0,0 +1,112 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lambda
+
+import (
+	"strings"
+
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/lambda/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/lambda/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// EventSourceMappingFieldNames describes the conversion of the community
+// EventSourceMapping fields into the official EventSourceMapping fields.
+var EventSourceMappingFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"eventSourceARN":              "eventSourceArn",
+		"documentDBEventSourceConfig": "documentDbEventSourceConfig",
+		"sourceAccessConfigurations":  "sourceAccessConfiguration",
+	},
+	Blocks: map[string]bool{
+		"amazonManagedKafkaEventSourceConfig": true,
+		"destinationConfig":                   true,
+		"documentDbEventSourceConfig":         true,
+		"filterCriteria":                      true,
+		"scalingConfig":                       true,
+		"selfManagedEventSource":              true,
+		"selfManagedKafkaEventSourceConfig":   true,
+	},
+	Paths: map[string]string{
+		"destinationConfig.onFailure.destination": "destinationConfig[0].onFailure[0].destinationArn",
+		"destinationConfig.onSuccess.destination": "destinationConfig[0].onSuccess[0].destinationArn",
+		"filterCriteria.filters":                  "filterCriteria[0].filter",
+	},
+	Transform: convertEventSourceMappingBlocks,
+}
+
+// convertEventSourceMappingBlocks converts the nested objects of the
+// destination config, the filters of the filter criteria and the endpoints
+// of the self-managed event source.
+func convertEventSourceMappingBlocks(forProvider map[string]any) {
+	if dc, ok := firstBlock(forProvider, "destinationConfig"); ok {
+		for _, k := range []string{"onFailure", "onSuccess"} {
+			d, ok := dc[k].(map[string]any)
+			if !ok {
+				continue
+			}
+			dc[k] = []any{map[string]any{
+				"destinationArn": d["destination"],
+			}}
+		}
+	}
+	if fc, ok := firstBlock(forProvider, "filterCriteria"); ok {
+		if filters, ok := fc["filters"]; ok {
+			delete(fc, "filters")
+			fc["filter"] = filters
+		}
+	}
+	if se, ok := firstBlock(forProvider, "selfManagedEventSource"); ok {
+		if endpoints, ok := se["endpoints"].(map[string]any); ok {
+			// the official endpoints are comma-separated lists
+			for k, v := range endpoints {
+				l, ok := v.([]any)
+				if !ok {
+					continue
+				}
+				hosts := make([]string, 0, len(l))
+				for _, h := range l {
+					if s, ok := h.(string); ok {
+						hosts = append(hosts, s)
+					}
+				}
+				endpoints[k] = strings.Join(hosts, ",")
+			}
+		}
+	}
+}
+
+// firstBlock returns the single element of the block with the given name.
+func firstBlock(m map[string]any, name string) (map[string]any, bool) {
+	l, ok := m[name].([]any)
+	if !ok || len(l) == 0 {
+		return nil, false
+	}
+	b, ok := l[0].(map[string]any)
+	return b, ok
+}
+
+func EventSourceMappingResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.EventSourceMapping)
+	target := &targetv1beta1.EventSourceMapping{}
+	if err := EventSourceMappingFieldNames.CopyInto(source, target, targetv1beta1.EventSourceMapping_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
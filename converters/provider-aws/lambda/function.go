@@ -0,0 +1,75 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lambda
+
+import (
+	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/lambda/v1beta1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/lambda/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// FunctionFieldNames describes the conversion of the community Function
+// fields into the official Function fields. The S3 and ECR code sources of
+// the community code object are top-level fields of the official Function.
+var FunctionFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"codeSigningConfigARN": "codeSigningConfigArn",
+		"fileSystemConfigs":    "fileSystemConfig",
+		"kmsKeyARN":            "kmsKeyArn",
+		"kmsKeyARNRef":         "kmsKeyArnRef",
+		"kmsKeyARNSelector":    "kmsKeyArnSelector",
+	},
+	Inline: map[string]map[string]string{
+		"code": {
+			"imageURI": "imageUri",
+		},
+	},
+	Blocks: map[string]bool{
+		"deadLetterConfig": true,
+		"environment":      true,
+		"ephemeralStorage": true,
+		"imageConfig":      true,
+		"snapStart":        true,
+		"tracingConfig":    true,
+		"vpcConfig":        true,
+	},
+	Items: map[string]map[string]string{
+		"deadLetterConfig": {
+			"targetARN": "targetArn",
+		},
+		"vpcConfig": {
+			"securityGroupIDs":        "securityGroupIds",
+			"securityGroupIDRefs":     "securityGroupIdRefs",
+			"securityGroupIDSelector": "securityGroupIdSelector",
+			"subnetIDs":               "subnetIds",
+			"subnetIDRefs":            "subnetIdRefs",
+			"subnetIDSelector":        "subnetIdSelector",
+		},
+	},
+	TagsMap: true,
+}
+
+func FunctionResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1beta1.Function)
+	target := &targetv1beta1.Function{}
+	if err := FunctionFieldNames.CopyInto(source, target, targetv1beta1.Function_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/rds/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/rds/v1beta1"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+const (
+	engineModeServerless  = "serverless"
+	engineModeProvisioned = "provisioned"
+)
+
+// ClusterFieldNames describes the conversion of the community DBCluster
+// fields into the official Cluster fields.
+var ClusterFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"domainIAMRoleName":                "domainIamRoleName",
+		"enableCloudwatchLogsExports":      "enabledCloudwatchLogsExports",
+		"enableHTTPEndpoint":               "enableHttpEndpoint",
+		"enableIAMDatabaseAuthentication":  "iamDatabaseAuthenticationEnabled",
+		"enablePerformanceInsights":        "performanceInsightsEnabled",
+		"finalDBSnapshotIdentifier":        "finalSnapshotIdentifier",
+		"kmsKeyID":                         "kmsKeyId",
+		"kmsKeyIDRef":                      "kmsKeyIdRef",
+		"kmsKeyIDSelector":                 "kmsKeyIdSelector",
+		"masterUserPasswordSecretRef":      "masterPasswordSecretRef",
+		"monitoringRoleARN":                "monitoringRoleArn",
+		"performanceInsightsKMSKeyID":      "performanceInsightsKmsKeyId",
+		"preSignedURL":                     "preSignedUrl",
+		"serverlessV2ScalingConfiguration": "serverlessv2ScalingConfiguration",
+		"vpcSecurityGroupIDs":              "vpcSecurityGroupIds",
+		"vpcSecurityGroupIDsRefs":          "vpcSecurityGroupIdRefs",
+		"vpcSecurityGroupIDsSelector":      "vpcSecurityGroupIdSelector",
+	},
+	Blocks: map[string]bool{
+		"scalingConfiguration":             true,
+		"serverlessv2ScalingConfiguration": true,
+	},
+}
+
+// ClusterResource converts a community DBCluster into an official Cluster.
+// The scaling configurations not supported by the engine mode of the
+// cluster are reported and dropped.
+func ClusterResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.DBCluster)
+	target := &targetv1beta1.Cluster{}
+	if err := ClusterFieldNames.CopyInto(source, target, targetv1beta1.Cluster_GroupVersionKind, "spec.forProvider.autogeneratePassword"); err != nil {
+		return nil, err
+	}
+
+	engineMode := engineModeProvisioned
+	if source.Spec.ForProvider.EngineMode != nil {
+		engineMode = *source.Spec.ForProvider.EngineMode
+	}
+	switch engineMode {
+	case engineModeServerless:
+		if len(target.Spec.ForProvider.Serverlessv2ScalingConfiguration) > 0 {
+			common.Warnf("DBCluster %q: serverless v2 scaling configuration is not supported in the %s engine mode and is dropped", source.GetName(), engineMode)
+			target.Spec.ForProvider.Serverlessv2ScalingConfiguration = nil
+		}
+	default:
+		if len(target.Spec.ForProvider.ScalingConfiguration) > 0 {
+			common.Warnf("DBCluster %q: scaling configuration is only supported in the %s engine mode and is dropped", source.GetName(), engineModeServerless)
+			target.Spec.ForProvider.ScalingConfiguration = nil
+		}
+	}
+
+	if source.Spec.ForProvider.AutogeneratePassword && source.Spec.ForProvider.MasterUserPasswordSecretRef == nil {
+		return nil, autogeneratedPasswordError("DBCluster", "masterUserPasswordSecretRef", source)
+	}
+
+	return []resource.Managed{
+		target,
+	}, nil
+}
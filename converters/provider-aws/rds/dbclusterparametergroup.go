@@ -0,0 +1,51 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/rds/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/rds/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// ClusterParameterGroupFieldNames describes the conversion of the community
+// DBClusterParameterGroup fields into the official ClusterParameterGroup
+// fields.
+var ClusterParameterGroupFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"dbParameterGroupFamily": "family",
+		"parameters":             "parameter",
+	},
+	Items: map[string]map[string]string{
+		"parameter": {
+			"parameterName":  "name",
+			"parameterValue": "value",
+		},
+	},
+}
+
+func ClusterParameterGroupResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.DBClusterParameterGroup)
+	target := &targetv1beta1.ClusterParameterGroup{}
+	if err := ClusterParameterGroupFieldNames.CopyInto(source, target, targetv1beta1.ClusterParameterGroup_GroupVersionKind); err != nil {
+		return nil, err
+	}
+
+	return []resource.Managed{
+		target,
+	}, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/rds/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/rds/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// ClusterInstanceFieldNames describes the conversion of the community
+// DBInstance fields into the official ClusterInstance fields.
+var ClusterInstanceFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"caCertificateIdentifier":     "caCertIdentifier",
+		"dbClusterIdentifier":         "clusterIdentifier",
+		"dbClusterIdentifierRef":      "clusterIdentifierRef",
+		"dbClusterIdentifierSelector": "clusterIdentifierSelector",
+		"dbInstanceClass":             "instanceClass",
+		"enablePerformanceInsights":   "performanceInsightsEnabled",
+		"monitoringRoleARN":           "monitoringRoleArn",
+		"monitoringRoleARNRef":        "monitoringRoleArnRef",
+		"monitoringRoleARNSelector":   "monitoringRoleArnSelector",
+		"performanceInsightsKMSKeyID": "performanceInsightsKmsKeyId",
+	},
+}
+
+// ClusterInstanceResource converts a community DBInstance into an official
+// ClusterInstance. Only the DBInstances of a DB cluster are converted, the
+// standalone DBInstances are not supported.
+func ClusterInstanceResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.DBInstance)
+	if !inCluster(source) {
+		return nil, errors.Errorf("DBInstance %q: the instance is not in a DB cluster, only the DBInstances with a dbClusterIdentifier can be converted", source.GetName())
+	}
+	target := &targetv1beta1.ClusterInstance{}
+	if err := ClusterInstanceFieldNames.CopyInto(source, target, targetv1beta1.ClusterInstance_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	return []resource.Managed{
+		target,
+	}, nil
+}
+
+// inCluster returns true if the given community DBInstance is in a DB
+// cluster, i.e., its DB cluster identifier is set or resolved with a
+// reference or a selector.
+func inCluster(source *srcv1alpha1.DBInstance) bool {
+	pv := fieldpath.Pave(migration.ToSanitizedUnstructured(source).Object)
+	for _, f := range []string{"dbClusterIdentifier", "dbClusterIdentifierRef", "dbClusterIdentifierSelector"} {
+		if v, err := pv.GetValue("spec.forProvider." + f); err == nil && v != nil && v != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -15,18 +15,79 @@
 package rds
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/database/v1beta1"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
-	"github.com/crossplane/upjet/pkg/migration"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/pkg/errors"
 	targetv1beta1 "github.com/upbound/provider-aws/apis/rds/v1beta1"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
 )
 
+const (
+	keyPassword = "password"
+)
+
+// ConnectionSecretCompatibility configures whether the connection details
+// of the converted composed RDSInstances are rewritten so that the
+// composite resources keep on publishing the connection secret keys of the
+// community RDSInstance with the same values.
+var ConnectionSecretCompatibility = false
+
+// instanceFieldNames maps the spec.forProvider fields of the community
+// RDSInstance to the renamed spec.forProvider fields of the official
+// Instance. The parameter group and the subnet group references are kept
+// separate as they refer to different official resources.
+var instanceFieldNames = providerawscommon.FieldNames{Renames: map[string]string{
+	"applyModificationsImmediately":   "applyImmediately",
+	"caCertificateIdentifier":         "caCertIdentifier",
+	"dbInstanceClass":                 "instanceClass",
+	"dbParameterGroupName":            "parameterGroupName",
+	"dbParameterGroupNameRef":         "parameterGroupNameRef",
+	"dbParameterGroupNameSelector":    "parameterGroupNameSelector",
+	"dbSnapshotIdentifier":            "snapshotIdentifier",
+	"domainIAMRoleName":               "domainIamRoleName",
+	"enableCloudwatchLogsExports":     "enabledCloudwatchLogsExports",
+	"enableIAMDatabaseAuthentication": "iamDatabaseAuthenticationEnabled",
+	"enablePerformanceInsights":       "performanceInsightsEnabled",
+	"finalDBSnapshotIdentifier":       "finalSnapshotIdentifier",
+	"kmsKeyID":                        "kmsKeyId",
+	"kmsKeyIDRef":                     "kmsKeyIdRef",
+	"kmsKeyIDSelector":                "kmsKeyIdSelector",
+	"masterPasswordSecretRef":         "passwordSecretRef",
+	"masterUsername":                  "username",
+	"monitoringRoleARN":               "monitoringRoleArn",
+	"monitoringRoleARNRef":            "monitoringRoleArnRef",
+	"monitoringRoleARNSelector":       "monitoringRoleArnSelector",
+	"multiAZ":                         "multiAz",
+	"performanceInsightsKMSKeyID":     "performanceInsightsKmsKeyId",
+	"preferredBackupWindow":           "backupWindow",
+	"preferredMaintenanceWindow":      "maintenanceWindow",
+	"skipFinalSnapshotBeforeDeletion": "skipFinalSnapshot",
+	"vpcSecurityGroupIDRefs":          "vpcSecurityGroupIdRefs",
+	"vpcSecurityGroupIDSelector":      "vpcSecurityGroupIdSelector",
+	"vpcSecurityGroupIDs":             "vpcSecurityGroupIds",
+}}
+
+// connectionSecretKeys maps the keys of the community RDSInstance
+// connection secrets whose values are published with different keys by the
+// official Instance to those keys. The official endpoint key contains the
+// port in addition to the address.
+var connectionSecretKeys = map[string]string{
+	"endpoint":  "address",
+	keyPassword: "attribute.password",
+}
+
 func InstanceResource(mg resource.Managed) ([]resource.Managed, error) {
 	source := mg.(*srcv1beta1.RDSInstance)
 	target := &targetv1beta1.Instance{}
-	if _, err := migration.CopyInto(source, target, targetv1beta1.Instance_GroupVersionKind, "spec.forProvider.tags"); err != nil {
-		return nil, errors.Wrap(err, "failed to copy source into target")
+	if err := instanceFieldNames.CopyInto(source, target, targetv1beta1.Instance_GroupVersionKind, "spec.forProvider.tags", "spec.forProvider.autogeneratePassword"); err != nil {
+		return nil, err
 	}
 	target.Spec.ForProvider.Tags = make(map[string]*string, len(source.Spec.ForProvider.Tags))
 	for _, t := range source.Spec.ForProvider.Tags {
@@ -34,15 +95,68 @@ func InstanceResource(mg resource.Managed) ([]resource.Managed, error) {
 		target.Spec.ForProvider.Tags[t.Key] = &v
 	}
 
-	target.Spec.ForProvider.ApplyImmediately = source.Spec.ForProvider.ApplyModificationsImmediately
-	target.Spec.ForProvider.CACertIdentifier = source.Spec.ForProvider.CACertificateIdentifier
-	target.Spec.ForProvider.IAMDatabaseAuthenticationEnabled = source.Spec.ForProvider.EnableIAMDatabaseAuthentication
-	target.Spec.ForProvider.PerformanceInsightsEnabled = source.Spec.ForProvider.EnablePerformanceInsights
-	target.Spec.ForProvider.FinalSnapshotIdentifier = source.Spec.ForProvider.FinalDBSnapshotIdentifier
-	target.Spec.ForProvider.InstanceClass = &source.Spec.ForProvider.DBInstanceClass
-	target.Spec.ForProvider.Username = source.Spec.ForProvider.MasterUsername
+	if source.Spec.ForProvider.AutogeneratePassword && source.Spec.ForProvider.MasterPasswordSecretRef == nil {
+		return nil, autogeneratedPasswordError("RDSInstance", "masterPasswordSecretRef", source)
+	}
+
+	if ref := source.GetWriteConnectionSecretToReference(); ref != nil {
+		common.Warnf("RDSInstance %q: the connection secret %s/%s will have the changed keys: %s", source.GetName(), ref.Namespace, ref.Name, connectionSecretKeyReport())
+	}
 
 	return []resource.Managed{
 		target,
 	}, nil
 }
+
+// autogeneratedPasswordError returns the error for the given resource
+// whose password is generated by the community provider. The generated
+// password is only stored in the connection secret owned by the community
+// resource, which is garbage collected with it, and the official provider
+// would rotate the password if asked to generate one. So, the password must
+// be copied into a dedicated Secret referred with the given field before
+// the migration.
+func autogeneratedPasswordError(kind, field string, source resource.Managed) error {
+	return errors.Errorf("%s %q: the password is autogenerated and only stored in the connection secret of the resource, copy it into a dedicated Secret and refer to it with spec.forProvider.%s before migrating", kind, source.GetName(), field)
+}
+
+// connectionSecretKeyReport returns a human-readable report of the changed
+// connection secret keys.
+func connectionSecretKeyReport() string {
+	keys := make([]string, 0, len(connectionSecretKeys))
+	for s, t := range connectionSecretKeys {
+		keys = append(keys, fmt.Sprintf("%s -> %s", s, t))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+// InstanceComposition converts the patches of a composed community
+// RDSInstance. If ConnectionSecretCompatibility is enabled, the connection
+// details read from the changed connection secret keys are rewritten to read
+// from the official keys and to keep on publishing the community keys.
+func InstanceComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	patchesToAdd, err := instanceFieldNames.ConvertPatches(sourceTemplate)
+	if err != nil {
+		return err
+	}
+	for i := range convertedTemplates {
+		convertedTemplates[i].Patches = append(convertedTemplates[i].Patches, patchesToAdd...)
+		if !ConnectionSecretCompatibility {
+			continue
+		}
+		for j, cd := range convertedTemplates[i].ConnectionDetails {
+			if cd.FromConnectionSecretKey == nil {
+				continue
+			}
+			k, ok := connectionSecretKeys[*cd.FromConnectionSecretKey]
+			if !ok {
+				continue
+			}
+			if cd.Name == nil {
+				convertedTemplates[i].ConnectionDetails[j].Name = common.PtrFromString(*cd.FromConnectionSecretKey)
+			}
+			convertedTemplates[i].ConnectionDetails[j].FromConnectionSecretKey = common.PtrFromString(k)
+		}
+	}
+	return nil
+}
@@ -17,29 +17,40 @@
 package provideraws
 
 import (
+	acmv1beta1 "github.com/crossplane-contrib/provider-aws/apis/acm/v1beta1"
 	apigatewayv2v1alpha1 "github.com/crossplane-contrib/provider-aws/apis/apigatewayv2/v1alpha1"
 	apigatewayv2v1beta1 "github.com/crossplane-contrib/provider-aws/apis/apigatewayv2/v1beta1"
 	cachev1alpha1 "github.com/crossplane-contrib/provider-aws/apis/cache/v1alpha1"
 	cachev1beta1 "github.com/crossplane-contrib/provider-aws/apis/cache/v1beta1"
 	cloudfrontv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/cloudfront/v1alpha1"
+	cloudwatchv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/cloudwatch/v1alpha1"
 	cloudwatchlogsv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/cloudwatchlogs/v1alpha1"
 	databasev1beta1 "github.com/crossplane-contrib/provider-aws/apis/database/v1beta1"
 	docdbv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/docdb/v1alpha1"
 	dynamodbv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/dynamodb/v1alpha1"
 	ec2v1alpha1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1alpha1"
 	ec2v1beta1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1beta1"
+	ecrv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/ecr/v1alpha1"
+	ecrv1beta1 "github.com/crossplane-contrib/provider-aws/apis/ecr/v1beta1"
 	efsv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/efs/v1alpha1"
 	eksmanualv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/eks/manualv1alpha1"
 	eksv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/eks/v1alpha1"
 	eksv1beta1 "github.com/crossplane-contrib/provider-aws/apis/eks/v1beta1"
 	elasticachev1alpha1 "github.com/crossplane-contrib/provider-aws/apis/elasticache/v1alpha1"
+	elbv2v1alpha1 "github.com/crossplane-contrib/provider-aws/apis/elbv2/v1alpha1"
+	firehosev1alpha1 "github.com/crossplane-contrib/provider-aws/apis/firehose/v1alpha1"
+	iamv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/iam/v1alpha1"
 	iamv1beta1 "github.com/crossplane-contrib/provider-aws/apis/iam/v1beta1"
 	kafkav1alpha1 "github.com/crossplane-contrib/provider-aws/apis/kafka/v1alpha1"
+	kinesisv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/kinesis/v1alpha1"
 	kmsv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/kms/v1alpha1"
+	lambdav1alpha1 "github.com/crossplane-contrib/provider-aws/apis/lambda/v1alpha1"
+	lambdav1beta1 "github.com/crossplane-contrib/provider-aws/apis/lambda/v1beta1"
 	mqv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/mq/v1alpha1"
 	rdsv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/rds/v1alpha1"
 	route53v1alpha1 "github.com/crossplane-contrib/provider-aws/apis/route53/v1alpha1"
 	route53resolvermanualv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/route53resolver/manualv1alpha1"
+	route53resolverv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/route53resolver/v1alpha1"
 	s3v1alpha3 "github.com/crossplane-contrib/provider-aws/apis/s3/v1alpha3"
 	s3v1beta1 "github.com/crossplane-contrib/provider-aws/apis/s3/v1beta1"
 	secretsmanagerv1beta1 "github.com/crossplane-contrib/provider-aws/apis/secretsmanager/v1beta1"
@@ -47,19 +58,26 @@ import (
 	sqsv1beta1 "github.com/crossplane-contrib/provider-aws/apis/sqs/v1beta1"
 	"github.com/crossplane/upjet/pkg/migration"
 
+	"github.com/upbound/extensions-migration/converters/provider-aws/acm"
 	"github.com/upbound/extensions-migration/converters/provider-aws/apigatewayv2"
 	"github.com/upbound/extensions-migration/converters/provider-aws/cloudfront"
+	"github.com/upbound/extensions-migration/converters/provider-aws/cloudwatch"
 	"github.com/upbound/extensions-migration/converters/provider-aws/cloudwatchlogs"
 	"github.com/upbound/extensions-migration/converters/provider-aws/common"
 	"github.com/upbound/extensions-migration/converters/provider-aws/docdb"
 	"github.com/upbound/extensions-migration/converters/provider-aws/dynamodb"
 	"github.com/upbound/extensions-migration/converters/provider-aws/ec2"
+	"github.com/upbound/extensions-migration/converters/provider-aws/ecr"
 	"github.com/upbound/extensions-migration/converters/provider-aws/efs"
 	"github.com/upbound/extensions-migration/converters/provider-aws/eks"
 	"github.com/upbound/extensions-migration/converters/provider-aws/elasticache"
+	"github.com/upbound/extensions-migration/converters/provider-aws/elbv2"
+	"github.com/upbound/extensions-migration/converters/provider-aws/firehose"
 	"github.com/upbound/extensions-migration/converters/provider-aws/iam"
 	"github.com/upbound/extensions-migration/converters/provider-aws/kafka"
+	"github.com/upbound/extensions-migration/converters/provider-aws/kinesis"
 	"github.com/upbound/extensions-migration/converters/provider-aws/kms"
+	"github.com/upbound/extensions-migration/converters/provider-aws/lambda"
 	"github.com/upbound/extensions-migration/converters/provider-aws/mq"
 	"github.com/upbound/extensions-migration/converters/provider-aws/rds"
 	"github.com/upbound/extensions-migration/converters/provider-aws/route53"
@@ -73,6 +91,8 @@ import (
 // RegisterAllKnownConverters registers all known converters for provider-aws
 // All future API converters for the community AWS provider must be registered in this function for the correct GVK
 func RegisterAllKnownConverters(r *migration.Registry) {
+	r.RegisterAPIConversionFunctions(acmv1beta1.CertificateGroupVersionKind,
+		acm.CertificateResource, acm.CertificateComposition, common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(apigatewayv2v1alpha1.APIGroupVersionKind,
 		apigatewayv2.APIResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(apigatewayv2v1alpha1.APIMappingGroupVersionKind,
@@ -92,11 +112,15 @@ func RegisterAllKnownConverters(r *migration.Registry) {
 	r.RegisterAPIConversionFunctions(apigatewayv2v1beta1.VPCLinkGroupVersionKind,
 		apigatewayv2.VPCLinkV1Beta1Resource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(cloudfrontv1alpha1.DistributionGroupVersionKind,
-		cloudfront.DistributionResource, nil, common.DefaultPatchSetsConverter)
+		cloudfront.DistributionResource, cloudfront.DistributionComposition, common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(cloudfrontv1alpha1.ResponseHeadersPolicyGroupVersionKind,
 		cloudfront.ResponseHeadersPolicyResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(cloudwatchv1alpha1.MetricAlarmGroupVersionKind,
+		cloudwatch.MetricAlarmResource, cloudwatch.MetricAlarmFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(cloudwatchlogsv1alpha1.LogGroupGroupVersionKind,
-		cloudwatchlogs.LogGroupResource, nil, nil)
+		cloudwatchlogs.LogGroupResource, cloudwatchlogs.LogGroupFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(cloudwatchlogsv1alpha1.SubscriptionFilterGroupVersionKind,
+		cloudwatchlogs.SubscriptionFilterResource, cloudwatchlogs.SubscriptionFilterFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(docdbv1alpha1.DBClusterGroupVersionKind,
 		docdb.ClusterResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(docdbv1alpha1.DBClusterParameterGroupGroupVersionKind,
@@ -107,8 +131,18 @@ func RegisterAllKnownConverters(r *migration.Registry) {
 		docdb.SubnetGroupResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(dynamodbv1alpha1.TableGroupVersionKind,
 		dynamodb.TableResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(ec2v1beta1.AddressGroupVersionKind,
+		ec2.AddressResource, ec2.AddressFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(ec2v1alpha1.FlowLogGroupVersionKind,
 		ec2.FlowLogResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(ec2v1alpha1.InstanceGroupVersionKind,
+		ec2.InstanceResource, ec2.InstanceFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(ec2v1beta1.InternetGatewayGroupVersionKind,
+		ec2.InternetGatewayResource, ec2.InternetGatewayComposition, common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(ec2v1alpha1.LaunchTemplateGroupVersionKind,
+		ec2.LaunchTemplateResource, ec2.LaunchTemplateFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(ec2v1alpha1.LaunchTemplateVersionGroupVersionKind,
+		ec2.LaunchTemplateVersionResource, ec2.LaunchTemplateVersionComposition, common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(ec2v1beta1.NATGatewayGroupVersionKind,
 		ec2.NATGatewayResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(ec2v1beta1.RouteTableGroupVersionKind,
@@ -117,6 +151,10 @@ func RegisterAllKnownConverters(r *migration.Registry) {
 		ec2.SecurityGroupResource, ec2.SecurityGroupComposition, common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(ec2v1beta1.SubnetGroupVersionKind,
 		ec2.SubnetResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(ec2v1alpha1.TransitGatewayGroupVersionKind,
+		ec2.TransitGatewayResource, ec2.TransitGatewayFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(ec2v1alpha1.TransitGatewayRouteTableGroupVersionKind,
+		ec2.TransitGatewayRouteTableResource, ec2.TransitGatewayRouteTableFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(ec2v1alpha1.TransitGatewayVPCAttachmentGroupVersionKind,
 		ec2.TransitGatewayVPCAttachmentResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(ec2v1beta1.VPCGroupVersionKind,
@@ -125,6 +163,28 @@ func RegisterAllKnownConverters(r *migration.Registry) {
 		ec2.VPCCidrBlockResource, nil, nil)
 	r.RegisterAPIConversionFunctions(ec2v1alpha1.VPCEndpointGroupVersionKind,
 		ec2.VPCEndpointResource, nil, nil)
+	r.RegisterAPIConversionFunctions(ec2v1alpha1.VPCPeeringConnectionGroupVersionKind,
+		ec2.VPCPeeringConnectionResource, ec2.VPCPeeringConnectionFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(ecrv1alpha1.LifecyclePolicyGroupVersionKind,
+		ecr.LifecyclePolicyResource, migration.DefaultCompositionConverter(map[string]string{
+			"spec.forProvider.lifecyclePolicyText":    "spec.forProvider.policy",
+			"spec.forProvider.repositoryName":         "spec.forProvider.repository",
+			"spec.forProvider.repositoryNameRef":      "spec.forProvider.repositoryRef",
+			"spec.forProvider.repositoryNameSelector": "spec.forProvider.repositorySelector",
+		}), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(ecrv1beta1.RepositoryGroupVersionKind,
+		ecr.RepositoryResource, migration.DefaultCompositionConverter(map[string]string{
+			"spec.forProvider.encryptionConfiguration.encryptionType": "spec.forProvider.encryptionConfiguration[0].encryptionType",
+			"spec.forProvider.encryptionConfiguration.kmsKey":         "spec.forProvider.encryptionConfiguration[0].kmsKey",
+			"spec.forProvider.imageScanningConfiguration.scanOnPush":  "spec.forProvider.imageScanningConfiguration[0].scanOnPush",
+		}, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(ecrv1beta1.RepositoryPolicyGroupVersionKind,
+		ecr.RepositoryPolicyResource, migration.DefaultCompositionConverter(map[string]string{
+			"spec.forProvider.rawPolicy":              "spec.forProvider.policy",
+			"spec.forProvider.repositoryName":         "spec.forProvider.repository",
+			"spec.forProvider.repositoryNameRef":      "spec.forProvider.repositoryRef",
+			"spec.forProvider.repositoryNameSelector": "spec.forProvider.repositorySelector",
+		}), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(efsv1alpha1.FileSystemGroupVersionKind,
 		efs.FileSystemResource, migration.DefaultCompositionConverter(map[string]string{
 			"spec.forProvider.kmsKeyID":       "spec.forProvider.kmsKeyId",
@@ -143,9 +203,19 @@ func RegisterAllKnownConverters(r *migration.Registry) {
 	r.RegisterAPIConversionFunctions(eksv1alpha1.AddonGroupVersionKind,
 		eks.AddonResource, nil, nil)
 	r.RegisterAPIConversionFunctions(eksv1beta1.ClusterGroupVersionKind,
-		eks.ClusterResource, nil, nil)
+		eks.ClusterResource, eks.ClusterComposition, common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(eksmanualv1alpha1.NodeGroupGroupVersionKind,
-		eks.NodegroupResource, nil, nil)
+		eks.NodegroupResource, eks.NodeGroupFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(eksmanualv1alpha1.FargateProfileGroupVersionKind,
+		eks.FargateProfileResource, eks.FargateProfileFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(eksmanualv1alpha1.IdentityProviderConfigGroupVersionKind,
+		eks.IdentityProviderConfigResource, eks.IdentityProviderConfigComposition, common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(elbv2v1alpha1.ListenerGroupVersionKind,
+		elbv2.ListenerResource, elbv2.ListenerComposition, common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(elbv2v1alpha1.LoadBalancerGroupVersionKind,
+		elbv2.LoadBalancerResource, elbv2.LoadBalancerComposition, common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(elbv2v1alpha1.TargetGroupGroupVersionKind,
+		elbv2.TargetGroupResource, elbv2.TargetGroupFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(cachev1alpha1.CacheClusterGroupVersionKind,
 		elasticache.ClusterResource, migration.DefaultCompositionConverter(map[string]string{
 			"spec.forProvider.cacheParameterGroupName": "spec.forProvider.parameterGroupName",
@@ -160,20 +230,40 @@ func RegisterAllKnownConverters(r *migration.Registry) {
 		}, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(cachev1alpha1.CacheSubnetGroupGroupVersionKind,
 		elasticache.CacheSubnetGroupResource, nil, nil)
+	r.RegisterAPIConversionFunctions(firehosev1alpha1.DeliveryStreamGroupVersionKind,
+		firehose.DeliveryStreamResource, firehose.DeliveryStreamFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(iamv1beta1.AccessKeyGroupVersionKind,
+		iam.AccessKeyResource, iam.AccessKeyFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(iamv1beta1.GroupGroupVersionKind,
+		iam.GroupResource, migration.DefaultCompositionConverter(nil), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(iamv1beta1.GroupPolicyAttachmentGroupVersionKind,
+		iam.GroupPolicyAttachmentResource, iam.GroupPolicyAttachmentFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(iamv1beta1.GroupUserMembershipGroupVersionKind,
+		iam.GroupUserMembershipResource, iam.GroupUserMembershipComposition, common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(iamv1alpha1.InstanceProfileGroupVersionKind,
+		iam.InstanceProfileResource, iam.InstanceProfileFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(iamv1beta1.OpenIDConnectProviderGroupVersionKind,
+		iam.OpenIDConnectProviderResource, iam.OpenIDConnectProviderFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(iamv1beta1.PolicyGroupVersionKind,
 		iam.PolicyResource, migration.DefaultCompositionConverter(map[string]string{
 			"spec.forProvider.document": "spec.forProvider.policy",
 		}, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(iamv1beta1.RoleGroupVersionKind,
-		iam.RoleResource, migration.DefaultCompositionConverter(map[string]string{
-			"spec.forProvider.assumeRolePolicyDocument": "spec.forProvider.assumeRolePolicy",
-		}, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+		iam.RoleResource, iam.RoleFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(iamv1beta1.RolePolicyGroupVersionKind,
+		iam.RolePolicyResource, iam.RolePolicyFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(iamv1beta1.RolePolicyAttachmentGroupVersionKind,
 		iam.RolePolicyAttachmentResource, migration.DefaultCompositionConverter(map[string]string{
 			"spec.forProvider.roleName":         "spec.forProvider.role",
 			"spec.forProvider.roleNameRef":      "spec.forProvider.roleRef",
 			"spec.forProvider.roleNameSelector": "spec.forProvider.roleSelector",
 		}), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(iamv1beta1.UserGroupVersionKind,
+		iam.UserResource, iam.UserFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(iamv1beta1.UserPolicyAttachmentGroupVersionKind,
+		iam.UserPolicyAttachmentResource, iam.UserPolicyAttachmentFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(kinesisv1alpha1.StreamGroupVersionKind,
+		kinesis.StreamResource, kinesis.StreamFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(kmsv1alpha1.AliasGroupVersionKind,
 		kms.AliasResource, nil, nil)
 	r.RegisterAPIConversionFunctions(kmsv1alpha1.KeyGroupVersionKind,
@@ -181,24 +271,46 @@ func RegisterAllKnownConverters(r *migration.Registry) {
 			"spec.forProvider.enabled": "spec.forProvider.isEnabled",
 			"status.atProvider.keyID":  "status.atProvider.keyId",
 		}, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(lambdav1alpha1.EventSourceMappingGroupVersionKind,
+		lambda.EventSourceMappingResource, lambda.EventSourceMappingFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(lambdav1beta1.FunctionGroupVersionKind,
+		lambda.FunctionResource, lambda.FunctionFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(lambdav1alpha1.FunctionURLConfigGroupVersionKind,
+		lambda.FunctionURLConfigResource, lambda.FunctionURLConfigFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(lambdav1alpha1.PermissionGroupVersionKind,
+		lambda.PermissionResource, lambda.PermissionFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(mqv1alpha1.BrokerGroupVersionKind,
 		mq.BrokerResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(rdsv1alpha1.DBClusterGroupVersionKind,
+		rds.ClusterResource, rds.ClusterFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(rdsv1alpha1.DBClusterParameterGroupGroupVersionKind,
+		rds.ClusterParameterGroupResource, rds.ClusterParameterGroupFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(rdsv1alpha1.DBInstanceGroupVersionKind,
+		rds.ClusterInstanceResource, rds.ClusterInstanceFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(rdsv1alpha1.DBParameterGroupGroupVersionKind,
 		rds.ParameterGroupResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(rdsv1alpha1.GlobalClusterGroupVersionKind,
+		rds.GlobalClusterResource, rds.GlobalClusterFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(databasev1beta1.RDSInstanceGroupVersionKind,
-		rds.InstanceResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+		rds.InstanceResource, rds.InstanceComposition, common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(databasev1beta1.DBSubnetGroupGroupVersionKind,
 		rds.DBSubnetGroupResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(route53v1alpha1.HealthCheckGroupVersionKind,
+		route53.HealthCheckResource, route53.HealthCheckComposition, common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(route53v1alpha1.HostedZoneGroupVersionKind,
-		route53.HostedZoneResource, migration.DefaultCompositionConverter(nil, common.ConvertComposedTemplateTags), common.DefaultPatchSetsConverter)
+		route53.HostedZoneResource, route53.HostedZoneComposition, common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(route53v1alpha1.ResourceRecordSetGroupVersionKind,
-		route53.ResourceRecordSetResource, nil, nil)
+		route53.ResourceRecordSetResource, route53.ResourceRecordSetComposition, common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(route53resolvermanualv1alpha1.ResolverRuleAssociationGroupVersionKind,
-		route53resolver.ResolverRuleAssociationResource, nil, nil)
+		route53resolver.ResolverRuleAssociationResource, migration.DefaultCompositionConverter(nil), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(route53resolverv1alpha1.ResolverEndpointGroupVersionKind,
+		route53resolver.ResolverEndpointResource, route53resolver.ResolverEndpointFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
+	r.RegisterAPIConversionFunctions(route53resolverv1alpha1.ResolverRuleGroupVersionKind,
+		route53resolver.ResolverRuleResource, route53resolver.ResolverRuleFieldNames.CompositionConverter(), common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(s3v1alpha3.BucketPolicyGroupVersionKind,
 		s3.BucketPolicyResource, nil, nil)
 	r.RegisterAPIConversionFunctions(s3v1beta1.BucketGroupVersionKind,
-		s3.BucketResource, nil, nil)
+		s3.BucketResource, s3.BucketComposition, common.DefaultPatchSetsConverter)
 	r.RegisterAPIConversionFunctions(secretsmanagerv1beta1.SecretGroupVersionKind,
 		secretsmanager.SecretResource, migration.DefaultCompositionConverter(map[string]string{
 			"spec.forProvider.assumeRolePolicyDocument": "spec.forProvider.assumeRolePolicy",
@@ -0,0 +1,97 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route53
+
+import (
+	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/route53/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/pkg/errors"
+	targetv1beta1 "github.com/upbound/provider-aws/apis/route53/v1beta1"
+
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+// healthCheckFieldNames describes the conversion of the community
+// HealthCheck fields into the official HealthCheck fields. The health
+// check configuration is moved into spec.forProvider.
+var healthCheckFieldNames = providerawscommon.FieldNames{
+	Inline: map[string]map[string]string{
+		"healthCheckConfig": {
+			"childHealthChecks":        "childHealthchecks",
+			"enableSNI":                "enableSni",
+			"fullyQualifiedDomainName": "fqdn",
+			"healthThreshold":          "childHealthThreshold",
+			"iPAddress":                "ipAddress",
+			"inverted":                 "invertHealthcheck",
+			"routingControlARN":        "routingControlArn",
+		},
+	},
+	Paths: map[string]string{
+		"healthCheckConfig.alarmIdentifier.name":   "cloudwatchAlarmName",
+		"healthCheckConfig.alarmIdentifier.region": "cloudwatchAlarmRegion",
+	},
+	Transform: convertAlarmIdentifier,
+}
+
+// convertAlarmIdentifier converts the CloudWatch alarm identifier of the
+// community HealthCheck into the official CloudWatch alarm fields.
+func convertAlarmIdentifier(forProvider map[string]any) {
+	ai, ok := forProvider["alarmIdentifier"].(map[string]any)
+	delete(forProvider, "alarmIdentifier")
+	if !ok {
+		return
+	}
+	if v, ok := ai["name"]; ok {
+		forProvider["cloudwatchAlarmName"] = v
+	}
+	if v, ok := ai["region"]; ok {
+		forProvider["cloudwatchAlarmRegion"] = v
+	}
+}
+
+func HealthCheckResource(mg resource.Managed) ([]resource.Managed, error) {
+	source := mg.(*srcv1alpha1.HealthCheck)
+	target := &targetv1beta1.HealthCheck{}
+	if err := healthCheckFieldNames.CopyInto(source, target, targetv1beta1.HealthCheck_GroupVersionKind); err != nil {
+		return nil, err
+	}
+	// route53 health check is global resource
+	region := RegionFor(source)
+	target.Spec.ForProvider.Region = &region
+	return []resource.Managed{
+		target,
+	}, nil
+}
+
+// HealthCheckComposition converts the patches of a composed community
+// HealthCheck and sets the region of the converted HealthCheck in its
+// base.
+func HealthCheckComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	patchesToAdd, err := healthCheckFieldNames.ConvertPatches(sourceTemplate)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the HealthCheck patches")
+	}
+	for i := range convertedTemplates {
+		ok, err := setBaseRegion(convertedTemplates[i], targetv1beta1.HealthCheck_Kind)
+		if err != nil {
+			return errors.Wrap(err, "failed to set the region of the HealthCheck")
+		}
+		if ok {
+			convertedTemplates[i].Patches = append(convertedTemplates[i].Patches, patchesToAdd...)
+		}
+	}
+	return nil
+}
@@ -15,13 +15,29 @@
 package route53
 
 import (
+	"encoding/json"
+	"fmt"
+
 	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/route53/v1alpha1"
+	runtimev1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/upjet/pkg/migration"
 	"github.com/pkg/errors"
 	targetv1beta1 "github.com/upbound/provider-aws/apis/route53/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
 )
 
+const fieldRegion = "spec.forProvider.region"
+
+// HostedZoneResource converts a community HostedZone into an official Zone.
+// A private zone keeps its first VPC association in the Zone and each of its
+// other VPC associations is converted into a ZoneAssociation.
 func HostedZoneResource(mg resource.Managed) ([]resource.Managed, error) {
 	source := mg.(*srcv1alpha1.HostedZone)
 	target := &targetv1beta1.Zone{}
@@ -29,17 +45,158 @@ func HostedZoneResource(mg resource.Managed) ([]resource.Managed, error) {
 		return nil, errors.Wrap(err, "failed to copy source into target")
 	}
 	// route53 zone is global resource
-	region := "eu-central-1"
+	region := RegionFor(source)
 	target.Spec.ForProvider.Region = &region
 
+	zoneMRs := []resource.Managed{target}
+	// public zones do not have any VPC associations
+	if source.Spec.ForProvider.VPC == nil {
+		return zoneMRs, nil
+	}
 	target.Spec.ForProvider.VPC = []targetv1beta1.VPCParameters{
 		{
 			VPCID:         source.Spec.ForProvider.VPC.VPCID,
 			VPCIDSelector: source.Spec.ForProvider.VPC.VPCIDSelector,
 			VPCIDRef:      source.Spec.ForProvider.VPC.VPCIDRef,
+			VPCRegion:     source.Spec.ForProvider.VPC.VPCRegion,
 		},
 	}
-	return []resource.Managed{
-		target,
-	}, nil
+
+	vpcs, err := associatedVPCs(source)
+	if err != nil {
+		return nil, err
+	}
+	externalName := meta.GetExternalName(source)
+	for _, vpc := range vpcs {
+		if source.Spec.ForProvider.VPC.VPCID != nil && *source.Spec.ForProvider.VPC.VPCID == vpc[0] {
+			continue
+		}
+		za := &targetv1beta1.ZoneAssociation{}
+		za.SetGroupVersionKind(targetv1beta1.ZoneAssociation_GroupVersionKind)
+		if len(source.GetName()) > 0 {
+			za.SetName(fmt.Sprintf("%s-%s", source.GetName(), vpc[0]))
+		}
+		if len(source.Labels) > 0 {
+			za.Labels = make(map[string]string, len(source.Labels)+1)
+			for k, v := range source.Labels {
+				za.Labels[k] = v
+			}
+			za.Labels["resourceType"] = "ZoneAssociation"
+		}
+		// the external name of a ZoneAssociation is <zone ID>:<VPC ID>[:<VPC region>]
+		if len(externalName) > 0 {
+			en := fmt.Sprintf("%s:%s", externalName, vpc[0])
+			if vpc[1] != "" {
+				en = fmt.Sprintf("%s:%s", en, vpc[1])
+			}
+			meta.SetExternalName(za, en)
+		}
+		za.Spec.DeletionPolicy = source.Spec.DeletionPolicy
+		za.Spec.ProviderConfigReference = source.Spec.ProviderConfigReference
+		za.Spec.ForProvider.Region = &region
+		za.Spec.ForProvider.VPCID = common.PtrFromString(vpc[0])
+		if vpc[1] != "" {
+			za.Spec.ForProvider.VPCRegion = common.PtrFromString(vpc[1])
+		}
+		if len(externalName) > 0 {
+			za.Spec.ForProvider.ZoneID = &externalName
+		}
+		matchController := true
+		za.Spec.ForProvider.ZoneIDRef = &runtimev1.Reference{Name: source.GetName()}
+		za.Spec.ForProvider.ZoneIDSelector = &runtimev1.Selector{MatchControllerRef: &matchController}
+		zoneMRs = append(zoneMRs, za)
+	}
+	return zoneMRs, nil
+}
+
+// associatedVPCs returns the IDs and regions of the VPCs observed to be
+// associated with the given private HostedZone.
+func associatedVPCs(source *srcv1alpha1.HostedZone) ([][2]string, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(source)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert the source HostedZone to unstructured")
+	}
+	v, err := fieldpath.Pave(u).GetValue("status.atProvider.vpcs")
+	if err != nil {
+		if fieldpath.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get the VPCs associated with the source HostedZone")
+	}
+	l, _ := v.([]any)
+	vpcs := make([][2]string, 0, len(l))
+	for _, e := range l {
+		m, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		id := firstString(m, "vpcID", "vpcId")
+		if id == "" {
+			continue
+		}
+		vpcs = append(vpcs, [2]string{id, firstString(m, "vpcRegion")})
+	}
+	return vpcs, nil
+}
+
+func firstString(m map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// HostedZoneComposition converts the patches of a composed community
+// HostedZone and sets the region of the converted Zone in its base so that
+// the region can still be overridden with patches.
+func HostedZoneComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	patchesToAdd, err := providerawscommon.ConvertComposedTemplateTags(sourceTemplate)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert tags")
+	}
+	patchesToAdd = append(patchesToAdd, migration.ConvertComposedTemplatePatchesMap(sourceTemplate, map[string]string{
+		"spec.forProvider.vpc.vpcId":         "spec.forProvider.vpc[0].vpcId",
+		"spec.forProvider.vpc.vpcIdRef":      "spec.forProvider.vpc[0].vpcIdRef",
+		"spec.forProvider.vpc.vpcIdSelector": "spec.forProvider.vpc[0].vpcIdSelector",
+		"spec.forProvider.vpc.vpcRegion":     "spec.forProvider.vpc[0].vpcRegion",
+	})...)
+	for i := range convertedTemplates {
+		ok, err := setBaseRegion(convertedTemplates[i], targetv1beta1.Zone_Kind)
+		if err != nil {
+			return errors.Wrap(err, "failed to set the region of the Zone")
+		}
+		if ok {
+			convertedTemplates[i].Patches = append(convertedTemplates[i].Patches, patchesToAdd...)
+		}
+	}
+	return nil
+}
+
+// setBaseRegion sets the region of the given converted template if its
+// base is of the given kind and has no region. Returns true if the base is
+// of the given kind.
+func setBaseRegion(t *v1.ComposedTemplate, kind string) (bool, error) {
+	u, err := migration.FromRawExtension(t.Base)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to convert ComposedTemplate base")
+	}
+	if u.GetKind() != kind {
+		return false, nil
+	}
+	paved := fieldpath.Pave(u.Object)
+	if _, err := paved.GetString(fieldRegion); err == nil {
+		return true, nil
+	}
+	pc, _ := paved.GetString("spec.providerConfigRef.name")
+	if err := paved.SetString(fieldRegion, regionForProviderConfig(pc)); err != nil {
+		return true, errors.Wrap(err, "failed to set the region")
+	}
+	raw, err := json.Marshal(u.Object)
+	if err != nil {
+		return true, errors.Wrapf(err, "failed to marshal the %s", kind)
+	}
+	t.Base.Raw = raw
+	return true, nil
 }
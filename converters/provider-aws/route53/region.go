@@ -0,0 +1,76 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route53
+
+import (
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// DefaultRegion is the region set on the converted Route53 resources when
+// neither a region has been set with SetRegion nor one has been set for
+// their ProviderConfig with SetProviderConfigRegion. Route53 is a global
+// service and the region only selects the API endpoint used.
+var DefaultRegion = "us-east-1"
+
+var (
+	regionMu              sync.RWMutex
+	region                string
+	providerConfigRegions = map[string]string{}
+)
+
+// SetRegion sets the region of all the converted Route53 resources,
+// overriding the ProviderConfig regions and the DefaultRegion. An empty
+// region resets the override.
+func SetRegion(r string) {
+	regionMu.Lock()
+	defer regionMu.Unlock()
+	region = r
+}
+
+// SetProviderConfigRegion sets the region of the converted Route53
+// resources referring to the ProviderConfig with the given name.
+func SetProviderConfigRegion(providerConfig, r string) {
+	regionMu.Lock()
+	defer regionMu.Unlock()
+	providerConfigRegions[providerConfig] = r
+}
+
+// RegionFor returns the region for the Route53 resources converted from the
+// given community resource.
+func RegionFor(mg resource.Managed) string {
+	pc := ""
+	if ref := mg.GetProviderConfigReference(); ref != nil {
+		pc = ref.Name
+	}
+	return regionForProviderConfig(pc)
+}
+
+func regionForProviderConfig(pc string) string {
+	regionMu.RLock()
+	defer regionMu.RUnlock()
+	if region != "" {
+		return region
+	}
+	// resources with no ProviderConfig reference use the default one
+	if pc == "" {
+		pc = "default"
+	}
+	if r := providerConfigRegions[pc]; r != "" {
+		return r
+	}
+	return DefaultRegion
+}
@@ -15,31 +15,193 @@
 package route53
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	srcv1alpha1 "github.com/crossplane-contrib/provider-aws/apis/route53/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
-	"github.com/crossplane/upjet/pkg/migration"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/pkg/errors"
 	targetv1beta1 "github.com/upbound/provider-aws/apis/route53/v1beta1"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
+)
+
+const (
+	fieldName              = "spec.forProvider.name"
+	annotationExternalName = "metadata.annotations[crossplane.io/external-name]"
 )
 
+// resourceRecordPath matches the patches of the values of the community
+// resource records.
+var resourceRecordPath = regexp.MustCompile(`^spec\.forProvider\.resourceRecords(\[\d+])\.value$`)
+
+// recordFieldNames describes the conversion of the community
+// ResourceRecordSet fields into the official Record fields. The latency
+// routing region of the community ResourceRecordSet is its region.
+var recordFieldNames = providerawscommon.FieldNames{
+	Renames: map[string]string{
+		"aliasTarget":      "alias",
+		"geoLocation":      "geolocationRoutingPolicy",
+		"multiValueAnswer": "multivalueAnswerRoutingPolicy",
+	},
+	Blocks: map[string]bool{
+		"alias":                    true,
+		"geolocationRoutingPolicy": true,
+	},
+	Items: map[string]map[string]string{
+		"alias": {
+			"dnsName":      "name",
+			"hostedZoneId": "zoneId",
+		},
+		"geolocationRoutingPolicy": {
+			"continentCode":   "continent",
+			"countryCode":     "country",
+			"subdivisionCode": "subdivision",
+		},
+	},
+	Paths: map[string]string{
+		"failover": "failoverRoutingPolicy[0].type",
+		"region":   "latencyRoutingPolicy[0].region",
+		"weight":   "weightedRoutingPolicy[0].weight",
+	},
+	Transform: convertRoutingPolicies,
+	TagsMap:   true,
+}
+
+// convertRoutingPolicies converts the resource records and the routing
+// policy parameters of the community ResourceRecordSet into the official
+// records and routing policy blocks.
+func convertRoutingPolicies(forProvider map[string]any) {
+	if l, ok := forProvider["resourceRecords"].([]any); ok {
+		delete(forProvider, "resourceRecords")
+		records := make([]any, 0, len(l))
+		for _, r := range l {
+			if rm, ok := r.(map[string]any); ok {
+				records = append(records, rm["value"])
+			}
+		}
+		forProvider["records"] = records
+	}
+	for s, t := range map[string][2]string{
+		"failover": {"failoverRoutingPolicy", "type"},
+		"region":   {"latencyRoutingPolicy", "region"},
+		"weight":   {"weightedRoutingPolicy", "weight"},
+	} {
+		v, ok := forProvider[s]
+		if !ok {
+			continue
+		}
+		delete(forProvider, s)
+		if v == "" {
+			continue
+		}
+		forProvider[t[0]] = []any{map[string]any{t[1]: v}}
+	}
+}
+
+// ResourceRecordSetResource converts a community ResourceRecordSet into an
+// official Record. The external name of the Record is
+// <zone ID>_<name>_<type>[_<set identifier>].
 func ResourceRecordSetResource(mg resource.Managed) ([]resource.Managed, error) {
 	source := mg.(*srcv1alpha1.ResourceRecordSet)
 	target := &targetv1beta1.Record{}
-	if _, err := migration.CopyInto(source, target, targetv1beta1.Record_GroupVersionKind); err != nil {
-		return nil, errors.Wrap(err, "failed to copy source into target")
-	}
-	target.Spec.ForProvider.Records = []*string{}
-	for _, t := range source.Spec.ForProvider.ResourceRecords {
-		v := t.Value
-		target.Spec.ForProvider.Records = append(target.Spec.ForProvider.Records, &v)
+	if err := recordFieldNames.CopyInto(source, target, targetv1beta1.Record_GroupVersionKind); err != nil {
+		return nil, err
 	}
+	// route53 record is global resource
+	region := RegionFor(source)
+	target.Spec.ForProvider.Region = &region
 
-	externalName := source.Annotations["crossplane.io/external-name"]
-	if len(externalName) > 0 {
-		target.Spec.ForProvider.Name = &externalName
+	externalName := meta.GetExternalName(source)
+	if len(externalName) == 0 {
+		return []resource.Managed{
+			target,
+		}, nil
 	}
-
+	target.Spec.ForProvider.Name = &externalName
+	zoneID := source.Spec.ForProvider.ZoneID
+	if zoneID == nil || *zoneID == "" {
+		common.Warnf("ResourceRecordSet %q: the hosted zone ID is not known, the external name of the Record cannot be set", source.GetName())
+		return []resource.Managed{
+			target,
+		}, nil
+	}
+	en := fmt.Sprintf("%s_%s_%s", *zoneID, strings.ToLower(strings.TrimSuffix(externalName, ".")), source.Spec.ForProvider.Type)
+	if source.Spec.ForProvider.SetIdentifier != nil && *source.Spec.ForProvider.SetIdentifier != "" {
+		en = fmt.Sprintf("%s_%s", en, *source.Spec.ForProvider.SetIdentifier)
+	}
+	meta.SetExternalName(target, en)
 	return []resource.Managed{
 		target,
 	}, nil
 }
+
+// recordFieldPath converts the given community ResourceRecordSet field
+// path into the official Record field path.
+func recordFieldPath(path *string) (string, bool) {
+	if path == nil {
+		return "", false
+	}
+	if m := resourceRecordPath.FindStringSubmatch(*path); m != nil {
+		return "spec.forProvider.records" + m[1], true
+	}
+	// the external name of a community ResourceRecordSet is the name of
+	// the record
+	if *path == annotationExternalName {
+		return fieldName, true
+	}
+	return recordFieldNames.RenameFieldPath(path)
+}
+
+// ResourceRecordSetComposition converts the patches of a composed
+// community ResourceRecordSet and sets the region of the converted Record
+// in its base. The patches of the external name are converted into the
+// patches of the record name, as the external name of a Record also
+// contains its zone ID and type.
+func ResourceRecordSetComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	var patchesToAdd []v1.Patch
+	for _, p := range sourceTemplate.Patches {
+		switch p.Type { //nolint:exhaustive
+		case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite, "":
+			if to, ok := recordFieldPath(p.ToFieldPath); ok {
+				p.ToFieldPath = &to
+				patchesToAdd = append(patchesToAdd, p)
+			}
+		case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeCombineToComposite:
+			if from, ok := recordFieldPath(p.FromFieldPath); ok {
+				p.FromFieldPath = &from
+				patchesToAdd = append(patchesToAdd, p)
+			}
+		}
+	}
+	for i := range convertedTemplates {
+		ok, err := setBaseRegion(convertedTemplates[i], targetv1beta1.Record_Kind)
+		if err != nil {
+			return errors.Wrap(err, "failed to set the region of the Record")
+		}
+		if !ok {
+			continue
+		}
+		patches := make([]v1.Patch, 0, len(convertedTemplates[i].Patches)+len(patchesToAdd))
+		for _, p := range convertedTemplates[i].Patches {
+			if !isExternalNamePatch(p) {
+				patches = append(patches, p)
+			}
+		}
+		convertedTemplates[i].Patches = append(patches, patchesToAdd...)
+	}
+	return nil
+}
+
+// isExternalNamePatch returns true if the given patch sets the external
+// name of the composed resource.
+func isExternalNamePatch(p v1.Patch) bool {
+	if p.Type == v1.PatchTypeToCompositeFieldPath || p.Type == v1.PatchTypeCombineToComposite {
+		return false
+	}
+	return p.ToFieldPath != nil && *p.ToFieldPath == annotationExternalName
+}
@@ -15,22 +15,592 @@
 package s3
 
 import (
+	"fmt"
+	"strings"
+
 	srcv1beta1 "github.com/crossplane-contrib/provider-aws/apis/s3/v1beta1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/upjet/pkg/migration"
 	"github.com/pkg/errors"
 	targetv1beta1 "github.com/upbound/provider-aws/apis/s3/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/upbound/extensions-migration/converters/common"
+	providerawscommon "github.com/upbound/extensions-migration/converters/provider-aws/common"
 )
 
+const (
+	fieldForProvider     = "spec.forProvider"
+	fieldACL             = "acl"
+	fieldObjectOwnership = "objectOwnership"
+	fieldPolicy          = "policy"
+	fieldTagSet          = "tagging.tagSet"
+	fieldTags            = "tags"
+)
+
+// bucketSubresource is an official S3 resource split from an inline
+// configuration of the community Bucket.
+type bucketSubresource struct {
+	gvk       schema.GroupVersionKind
+	newTarget func() resource.Managed
+	// source is the field path of the inline configuration relative to
+	// spec.forProvider of the community Bucket.
+	source string
+	// target is the field path of the converted configuration relative to
+	// spec.forProvider of the sub-resource. If empty, the converted
+	// configuration is the spec.forProvider itself.
+	target string
+	// block is true if the inline configuration object is a block,
+	// i.e., a single element list, in the sub-resource.
+	block bool
+	// suffix is appended to the name of the Bucket to name the
+	// sub-resource.
+	suffix string
+}
+
+// bucketOwnershipControls also converts the object ownership of the
+// community Bucket if the Bucket has no ownership controls.
+var bucketOwnershipControls = bucketSubresource{
+	gvk:       targetv1beta1.BucketOwnershipControls_GroupVersionKind,
+	newTarget: func() resource.Managed { return &targetv1beta1.BucketOwnershipControls{} },
+	source:    "ownershipControls.rules",
+	target:    "rule",
+	suffix:    "ownership-controls",
+}
+
+// bucketGrants are the grant fields of the community Bucket. The official
+// BucketACL grants also require the canonical user ID of the bucket owner,
+// which the community Bucket does not have, so they are not converted.
+var bucketGrants = []string{"grantFullControl", "grantRead", "grantReadACP", "grantWrite", "grantWriteACP"}
+
+var bucketSubresources = []bucketSubresource{
+	{
+		gvk:       targetv1beta1.BucketVersioning_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketVersioning{} },
+		source:    "versioningConfiguration",
+		target:    "versioningConfiguration",
+		block:     true,
+		suffix:    "versioning",
+	},
+	{
+		gvk:       targetv1beta1.BucketServerSideEncryptionConfiguration_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketServerSideEncryptionConfiguration{} },
+		source:    "serverSideEncryptionConfiguration.rules",
+		target:    "rule",
+		suffix:    "encryption",
+	},
+	{
+		gvk:       targetv1beta1.BucketLifecycleConfiguration_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketLifecycleConfiguration{} },
+		source:    "lifecycleConfiguration.rules",
+		target:    "rule",
+		suffix:    "lifecycle",
+	},
+	{
+		gvk:       targetv1beta1.BucketPublicAccessBlock_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketPublicAccessBlock{} },
+		source:    "publicAccessBlockConfiguration",
+		suffix:    "public-access-block",
+	},
+	{
+		gvk:       targetv1beta1.BucketCorsConfiguration_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketCorsConfiguration{} },
+		source:    "corsConfiguration.corsRules",
+		target:    "corsRule",
+		suffix:    "cors",
+	},
+	{
+		gvk:       targetv1beta1.BucketWebsiteConfiguration_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketWebsiteConfiguration{} },
+		source:    "websiteConfiguration",
+		suffix:    "website",
+	},
+	{
+		gvk:       targetv1beta1.BucketLogging_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketLogging{} },
+		source:    "loggingConfiguration",
+		suffix:    "logging",
+	},
+	bucketOwnershipControls,
+	{
+		gvk:       targetv1beta1.BucketReplicationConfiguration_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketReplicationConfiguration{} },
+		source:    "replicationConfiguration",
+		suffix:    "replication",
+	},
+	{
+		gvk:       targetv1beta1.BucketACL_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketACL{} },
+		source:    fieldACL,
+		target:    fieldACL,
+		suffix:    "acl",
+	},
+	{
+		gvk:       targetv1beta1.BucketPolicy_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketPolicy{} },
+		source:    fieldPolicy,
+		target:    fieldPolicy,
+		suffix:    "policy",
+	},
+	{
+		gvk:       targetv1beta1.BucketNotification_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketNotification{} },
+		source:    "notificationConfiguration",
+		suffix:    "notification",
+	},
+	{
+		gvk:       targetv1beta1.BucketAccelerateConfiguration_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketAccelerateConfiguration{} },
+		source:    "accelerateConfiguration",
+		suffix:    "accelerate",
+	},
+	{
+		gvk:       targetv1beta1.BucketRequestPaymentConfiguration_GroupVersionKind,
+		newTarget: func() resource.Managed { return &targetv1beta1.BucketRequestPaymentConfiguration{} },
+		source:    "payerConfiguration",
+		suffix:    "request-payment",
+	},
+}
+
+// bucketFieldNames maps the field names of the inline configurations of the
+// community Bucket to the field names in the official sub-resources.
+var bucketFieldNames = map[string]string{
+	"rules":                        "rule",
+	"corsRules":                    "corsRule",
+	"routingRules":                 "routingRule",
+	"transitions":                  "transition",
+	"noncurrentVersionTransitions": "noncurrentVersionTransition",
+	"targetGrants":                 "targetGrant",
+	"targetGrantee":                "grantee",
+	"bucketLogsPermission":         "permission",
+	"replicaKmsKeyID":              "replicaKmsKeyId",
+	"lambdaFunctionConfigurations": "lambdaFunction",
+	"queueConfigurations":          "queue",
+	"topicConfigurations":          "topic",
+}
+
+// bucketBlocks are the nested objects of the inline configurations of the
+// community Bucket that are blocks in the official sub-resources.
+var bucketBlocks = map[string]bool{
+	"abortIncompleteMultipartUpload":     true,
+	"accessControlTranslation":           true,
+	"and":                                true,
+	"applyServerSideEncryptionByDefault": true,
+	"condition":                          true,
+	"deleteMarkerReplication":            true,
+	"destination":                        true,
+	"encryptionConfiguration":            true,
+	"errorDocument":                      true,
+	"eventThreshold":                     true,
+	"existingObjectReplication":          true,
+	"expiration":                         true,
+	"filter":                             true,
+	"grantee":                            true,
+	"indexDocument":                      true,
+	"metrics":                            true,
+	"noncurrentVersionExpiration":        true,
+	"redirect":                           true,
+	"redirectAllRequestsTo":              true,
+	"replicationTime":                    true,
+	"sourceSelectionCriteria":            true,
+	"sseKmsEncryptedObjects":             true,
+	"tag":                                true,
+	"time":                               true,
+}
+
+// BucketResource converts a community Bucket into an official Bucket and
+// splits each of its inline configurations into the corresponding official
+// sub-resource referring to the converted Bucket.
 func BucketResource(mg resource.Managed) ([]resource.Managed, error) {
 	source := mg.(*srcv1beta1.Bucket)
+	skip := []string{"spec.forProvider.locationConstraint", "spec.forProvider.tagging", "spec.forProvider.objectLockEnabledForBucket",
+		fmt.Sprintf("%s.%s", fieldForProvider, fieldObjectOwnership)}
+	for _, g := range bucketGrants {
+		skip = append(skip, fmt.Sprintf("%s.%s", fieldForProvider, g))
+	}
+	for _, s := range bucketSubresources {
+		skip = append(skip, fmt.Sprintf("%s.%s", fieldForProvider, strings.Split(s.source, ".")[0]))
+	}
 	target := &targetv1beta1.Bucket{}
-	if _, err := migration.CopyInto(source, target, targetv1beta1.Bucket_GroupVersionKind); err != nil {
+	if _, err := migration.CopyInto(source, target, targetv1beta1.Bucket_GroupVersionKind, skip...); err != nil {
 		return nil, errors.Wrap(err, "failed to copy source into target")
 	}
-	target.Spec.ForProvider.Region = &source.Spec.ForProvider.LocationConstraint
+	region := source.Spec.ForProvider.LocationConstraint
+	target.Spec.ForProvider.Region = &region
+	target.Spec.ForProvider.ObjectLockEnabled = source.Spec.ForProvider.ObjectLockEnabledForBucket
 
-	return []resource.Managed{
-		target,
-	}, nil
+	pv := fieldpath.Pave(migration.ToSanitizedUnstructured(source).Object)
+	tags, err := pv.GetValue(fmt.Sprintf("%s.%s", fieldForProvider, fieldTagSet))
+	if err != nil && !fieldpath.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to get the tags of the source Bucket")
+	}
+	if t, ok := tagsToMap(tags); ok {
+		target.Spec.ForProvider.Tags = make(map[string]*string, len(t))
+		for k, v := range t {
+			target.Spec.ForProvider.Tags[k] = common.PtrFromString(fmt.Sprintf("%v", v))
+		}
+	}
+
+	bucketMRs := []resource.Managed{target}
+	for _, s := range bucketSubresources {
+		v, err := pv.GetValue(fmt.Sprintf("%s.%s", fieldForProvider, s.source))
+		if fieldpath.IsNotFound(err) || v == nil {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the inline configuration %q of the source Bucket", s.source)
+		}
+		mg, err := newBucketSubresource(source, s, v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the inline configuration %q of the source Bucket", s.source)
+		}
+		bucketMRs = append(bucketMRs, mg)
+	}
+
+	// the object ownership is converted into an ownership controls rule
+	// unless the source Bucket already has ownership controls
+	if o, err := pv.GetString(fmt.Sprintf("%s.%s", fieldForProvider, fieldObjectOwnership)); err == nil && o != "" {
+		if _, err := pv.GetValue(fmt.Sprintf("%s.%s", fieldForProvider, bucketOwnershipControls.source)); fieldpath.IsNotFound(err) {
+			mg, err := newBucketSubresource(source, bucketOwnershipControls, []any{map[string]any{fieldObjectOwnership: o}})
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to convert the object ownership of the source Bucket")
+			}
+			bucketMRs = append(bucketMRs, mg)
+		}
+	}
+	for _, g := range bucketGrants {
+		if v, err := pv.GetString(fmt.Sprintf("%s.%s", fieldForProvider, g)); err == nil && v != "" {
+			common.Warnf("Bucket %q: %s is not converted, the grants of the converted BucketACL need to be configured with an access control policy", source.GetName(), g)
+		}
+	}
+	return bucketMRs, nil
+}
+
+func newBucketSubresource(source *srcv1beta1.Bucket, s bucketSubresource, v any) (resource.Managed, error) {
+	var forProvider map[string]any
+	switch s.source {
+	case fieldPolicy:
+		p, err := providerawscommon.SerializePolicy(v)
+		if err != nil {
+			return nil, err
+		}
+		forProvider = map[string]any{fieldPolicy: p}
+	case "notificationConfiguration":
+		forProvider = convertBucketFields(convertNotificationFilters(v)).(map[string]any)
+	default:
+		converted := convertBucketFields(v)
+		if s.block {
+			converted = []any{converted}
+		}
+		if s.target == "" {
+			m, ok := converted.(map[string]any)
+			if !ok {
+				return nil, errors.Errorf("inline configuration %q is not an object", s.source)
+			}
+			forProvider = m
+		} else {
+			forProvider = map[string]any{s.target: converted}
+		}
+	}
+	forProvider["region"] = source.Spec.ForProvider.LocationConstraint
+	if len(source.GetName()) > 0 {
+		forProvider["bucketRef"] = map[string]any{"name": source.GetName()}
+	}
+	forProvider["bucketSelector"] = map[string]any{"matchControllerRef": true}
+
+	target := s.newTarget()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]any{
+		"spec": map[string]any{
+			"forProvider": forProvider,
+		},
+	}, target); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert into %s", s.gvk.Kind)
+	}
+	target.GetObjectKind().SetGroupVersionKind(s.gvk)
+	if len(source.GetName()) > 0 {
+		target.SetName(fmt.Sprintf("%s-%s", source.GetName(), s.suffix))
+	}
+	if len(source.GetLabels()) > 0 {
+		labels := make(map[string]string, len(source.GetLabels())+1)
+		for k, v := range source.GetLabels() {
+			labels[k] = v
+		}
+		labels["resourceType"] = s.gvk.Kind
+		target.SetLabels(labels)
+	}
+	// the sub-resources of a bucket are identified with the bucket name
+	if en := meta.GetExternalName(source); len(en) > 0 {
+		meta.SetExternalName(target, en)
+	}
+	target.SetDeletionPolicy(source.GetDeletionPolicy())
+	target.SetProviderConfigReference(source.GetProviderConfigReference())
+	return target, nil
+}
+
+// convertBucketFields converts an inline configuration value of the
+// community Bucket into its official representation by renaming the fields,
+// converting the nested objects into blocks, the numbers into float64 and
+// the tag lists into maps.
+func convertBucketFields(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			if k == fieldTags {
+				if tags, ok := tagsToMap(e); ok {
+					m[k] = tags
+					continue
+				}
+			}
+			if n, ok := bucketFieldNames[k]; ok {
+				k = n
+			}
+			converted := convertBucketFields(e)
+			if _, ok := converted.(map[string]any); ok && bucketBlocks[k] {
+				converted = []any{converted}
+			}
+			m[k] = converted
+		}
+		return m
+	case []any:
+		l := make([]any, 0, len(t))
+		for _, e := range t {
+			l = append(l, convertBucketFields(e))
+		}
+		return l
+	case int64:
+		return float64(t)
+	case int32:
+		return float64(t)
+	case int:
+		return float64(t)
+	default:
+		return v
+	}
+}
+
+// convertNotificationFilters converts the S3 key filter rules of the
+// community notification configurations into the filterPrefix and
+// filterSuffix fields of the official BucketNotification.
+func convertNotificationFilters(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	for _, k := range []string{"lambdaFunctionConfigurations", "queueConfigurations", "topicConfigurations"} {
+		configs, ok := m[k].([]any)
+		if !ok {
+			continue
+		}
+		for _, c := range configs {
+			cm, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			rules, err := fieldpath.Pave(cm).GetValue("filter.key.filterRules")
+			delete(cm, "filter")
+			if err != nil {
+				continue
+			}
+			rl, _ := rules.([]any)
+			for _, r := range rl {
+				rm, ok := r.(map[string]any)
+				if !ok {
+					continue
+				}
+				name, _ := rm["name"].(string)
+				switch strings.ToLower(name) {
+				case "prefix":
+					cm["filterPrefix"] = rm["value"]
+				case "suffix":
+					cm["filterSuffix"] = rm["value"]
+				}
+			}
+		}
+	}
+	return m
+}
+
+// tagsToMap converts a list of key/value tags into a map.
+func tagsToMap(v any) (map[string]any, bool) {
+	l, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+	m := make(map[string]any, len(l))
+	for _, t := range l {
+		tm, ok := t.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		k, ok := tm["key"].(string)
+		if !ok {
+			return nil, false
+		}
+		m[k] = tm["value"]
+	}
+	return m, true
+}
+
+// BucketComposition converts the patches of a composed community Bucket and
+// routes the patches of its inline configurations to the corresponding
+// official sub-resources.
+func BucketComposition(sourceTemplate v1.ComposedTemplate, convertedTemplates ...*v1.ComposedTemplate) error {
+	patchesToAdd, err := convertBucketTags(sourceTemplate)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert tags")
+	}
+	if err := common.SplittedResourcePatches(convertedTemplates, targetv1beta1.Bucket_Kind, patchesToAdd); err != nil {
+		return errors.Wrap(err, "failed to add patches to the Bucket")
+	}
+
+	region := migration.ConvertComposedTemplatePatchesMap(sourceTemplate, map[string]string{
+		"spec.forProvider.locationConstraint": "spec.forProvider.region",
+	})
+	for i := range convertedTemplates {
+		convertedTemplates[i].Patches = append(convertedTemplates[i].Patches, region...)
+	}
+
+	for _, s := range bucketSubresources {
+		var patches []v1.Patch
+		for _, p := range sourceTemplate.Patches {
+			if c, ok := convertBucketPatch(p, s); ok {
+				patches = append(patches, c)
+			}
+		}
+		if len(patches) == 0 {
+			continue
+		}
+		if err := common.SplittedResourcePatches(convertedTemplates, s.gvk.Kind, patches); err != nil {
+			return errors.Wrapf(err, "failed to add patches to the splitted resource %s", s.gvk.Kind)
+		}
+	}
+
+	ownership := migration.ConvertComposedTemplatePatchesMap(sourceTemplate, map[string]string{
+		fmt.Sprintf("%s.%s", fieldForProvider, fieldObjectOwnership): fmt.Sprintf("%s.rule[0].%s", fieldForProvider, fieldObjectOwnership),
+	})
+	if err := common.SplittedResourcePatches(convertedTemplates, bucketOwnershipControls.gvk.Kind, ownership); err != nil {
+		return errors.Wrapf(err, "failed to add patches to the splitted resource %s", bucketOwnershipControls.gvk.Kind)
+	}
+	for _, p := range sourceTemplate.Patches {
+		for _, g := range bucketGrants {
+			if p.ToFieldPath != nil && *p.ToFieldPath == fmt.Sprintf("%s.%s", fieldForProvider, g) {
+				common.Warnf("Bucket patch to %s is not converted, the grants of the converted BucketACL need to be configured with an access control policy", g)
+			}
+		}
+	}
+	return nil
+}
+
+// convertBucketTags converts the patches of the tags of a composed
+// community Bucket into the patches of the official Bucket's tags map.
+func convertBucketTags(sourceTemplate v1.ComposedTemplate) ([]v1.Patch, error) {
+	prefix := fmt.Sprintf("%s.%s", fieldForProvider, fieldTagSet)
+	var patchesToAdd []v1.Patch
+	for _, p := range sourceTemplate.Patches {
+		if p.ToFieldPath == nil || !strings.HasPrefix(*p.ToFieldPath, prefix) || !strings.HasSuffix(*p.ToFieldPath, ".value") {
+			continue
+		}
+		u, err := migration.FromRawExtension(sourceTemplate.Base)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert ComposedTemplate")
+		}
+		key, err := fieldpath.Pave(u.Object).GetString(strings.TrimSuffix(*p.ToFieldPath, ".value") + ".key")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get value from paved")
+		}
+		patchesToAdd = append(patchesToAdd, v1.Patch{
+			Type:          p.Type,
+			FromFieldPath: p.FromFieldPath,
+			ToFieldPath:   common.PtrFromString(fmt.Sprintf(`spec.forProvider.tags["%s"]`, key)),
+			Transforms:    p.Transforms,
+			Policy:        p.Policy,
+		})
+	}
+	return patchesToAdd, nil
+}
+
+// convertBucketPatch converts a patch to an inline configuration of the
+// community Bucket into a patch to the given sub-resource.
+func convertBucketPatch(p v1.Patch, s bucketSubresource) (v1.Patch, bool) {
+	if s.source == fieldPolicy {
+		// the structured policy cannot be patched into the
+		// serialized policy document.
+		return v1.Patch{}, false
+	}
+	if p.Type != v1.PatchTypeFromCompositeFieldPath && p.Type != v1.PatchTypeCombineFromComposite && p.Type != "" {
+		return v1.Patch{}, false
+	}
+	if p.ToFieldPath == nil {
+		return v1.Patch{}, false
+	}
+	prefix := fmt.Sprintf("%s.%s", fieldForProvider, s.source)
+	if !strings.HasPrefix(*p.ToFieldPath, prefix) {
+		return v1.Patch{}, false
+	}
+	rest := strings.TrimPrefix(*p.ToFieldPath, prefix)
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		return v1.Patch{}, false
+	}
+	targetPath := fieldForProvider
+	if s.target != "" {
+		targetPath = fmt.Sprintf("%s.%s", targetPath, s.target)
+	}
+	if s.block {
+		targetPath += "[0]"
+	}
+	converted, ok := convertBucketFieldPath(rest)
+	if !ok {
+		return v1.Patch{}, false
+	}
+	return v1.Patch{
+		Type:          p.Type,
+		FromFieldPath: p.FromFieldPath,
+		ToFieldPath:   common.PtrFromString(targetPath + converted),
+		Transforms:    p.Transforms,
+		Policy:        p.Policy,
+		Combine:       p.Combine,
+	}, true
+}
+
+// convertBucketFieldPath converts the given field path relative to an
+// inline configuration of the community Bucket into the field path relative
+// to the corresponding sub-resource configuration.
+func convertBucketFieldPath(path string) (string, bool) {
+	var b strings.Builder
+	if strings.HasPrefix(path, "[") {
+		i := strings.Index(path, "]")
+		if i < 0 {
+			return "", false
+		}
+		b.WriteString(path[:i+1])
+		path = path[i+1:]
+	}
+	if path == "" {
+		return b.String(), true
+	}
+	for _, seg := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		name, index := seg, ""
+		if i := strings.Index(seg, "["); i >= 0 {
+			name, index = seg[:i], seg[i:]
+		}
+		if name == fieldTags {
+			// the tag lists are converted into maps
+			return "", false
+		}
+		if n, ok := bucketFieldNames[name]; ok {
+			name = n
+		}
+		if bucketBlocks[name] && index == "" {
+			index = "[0]"
+		}
+		b.WriteString(".")
+		b.WriteString(name)
+		b.WriteString(index)
+	}
+	return b.String(), true
 }
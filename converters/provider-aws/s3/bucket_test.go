@@ -0,0 +1,120 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvertBucketFields(t *testing.T) {
+	cases := map[string]struct {
+		v    any
+		want any
+	}{
+		"LoggingWithTargetGrants": {
+			v: map[string]any{
+				"targetBucket": "logs",
+				"targetPrefix": "bucket/",
+				"targetGrants": []any{
+					map[string]any{
+						"bucketLogsPermission": "READ",
+						"targetGrantee": map[string]any{
+							"type": "CanonicalUser",
+							"id":   "abc",
+						},
+					},
+				},
+			},
+			want: map[string]any{
+				"targetBucket": "logs",
+				"targetPrefix": "bucket/",
+				"targetGrant": []any{
+					map[string]any{
+						"permission": "READ",
+						"grantee": []any{
+							map[string]any{
+								"type": "CanonicalUser",
+								"id":   "abc",
+							},
+						},
+					},
+				},
+			},
+		},
+		"LifecycleRules": {
+			v: map[string]any{
+				"rules": []any{
+					map[string]any{
+						"status": "Enabled",
+						"expiration": map[string]any{
+							"days": int64(30),
+						},
+						"filter": map[string]any{
+							"tag": map[string]any{
+								"key":   "k",
+								"value": "v",
+							},
+						},
+					},
+				},
+			},
+			want: map[string]any{
+				"rule": []any{
+					map[string]any{
+						"status": "Enabled",
+						"expiration": []any{
+							map[string]any{
+								"days": float64(30),
+							},
+						},
+						"filter": []any{
+							map[string]any{
+								"tag": []any{
+									map[string]any{
+										"key":   "k",
+										"value": "v",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"Tags": {
+			v: map[string]any{
+				"tags": []any{
+					map[string]any{
+						"key":   "k",
+						"value": "v",
+					},
+				},
+			},
+			want: map[string]any{
+				"tags": map[string]any{
+					"k": "v",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := convertBucketFields(tc.v); !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("\nconvertBucketFields(...): want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	sourceapis "github.com/crossplane-contrib/provider-aws/apis"
 	"github.com/crossplane/upjet/pkg/migration"
@@ -34,8 +35,15 @@ import (
 
 	"github.com/upbound/extensions-migration/converters/common"
 	provideraws "github.com/upbound/extensions-migration/converters/provider-aws"
+	"github.com/upbound/extensions-migration/converters/provider-aws/acm"
+	"github.com/upbound/extensions-migration/converters/provider-aws/rds"
+	"github.com/upbound/extensions-migration/converters/provider-aws/route53"
 )
 
+// warningsFile is the name of the file the migration warnings are
+// stored in, next to the migration plan.
+const warningsFile = "migration_warnings.txt"
+
 func main() {
 	// Common CLI Flags
 	// They can be extended according to requirements
@@ -46,6 +54,11 @@ func main() {
 		kubeconfigPath    = app.Flag("kubeconfig", "Path of the kubernetes config file. Defaults to ~/.kube/config ").String()
 		skipGVKsPath      = app.Flag("skip-gvks", "Path of the file containing the GVKs to skip").String()
 		setProviderConfig = app.Flag("set-provider-config", "Used to set a ProviderConfig Reference to all Managed Resources. The string specified for this flag is added as a ProviderConfig Reference to all MRs to be converted.").String()
+		rdsCompatibility  = app.Flag("rds-connection-secret-compatibility", "Rewrite the connection details of the composed RDSInstances so that the composite resources keep on publishing the connection secret keys of the community RDSInstances.").Bool()
+		acmZoneID         = app.Flag("acm-validation-zone-id", "ID of the Route53 hosted zone in which the DNS validation records of the converted ACM Certificates are created. If it's not specified, no CertificateValidations and validation Records are generated.").String()
+		acmReferences     = app.Flag("acm-certificate-references", "Select the converted ACM Certificates instead of their ARNs in the converted resources referring to them. Only the certificates converted by the migration can be selected, so all the referred certificates must be migrated.").Bool()
+		route53Region     = app.Flag("route53-region", "Region to set on the converted global Route53 resources. Defaults to "+route53.DefaultRegion+".").String()
+		route53PCRegions  = app.Flag("route53-provider-config-region", "Region to set on the converted global Route53 resources referring to a ProviderConfig, in the form <ProviderConfig name>=<region>. Can be repeated and is overridden by --route53-region.").StringMap()
 	)
 	if len(*kubeconfigPath) == 0 {
 		homeDir, err := os.UserHomeDir()
@@ -74,6 +87,13 @@ func main() {
 	// Registry.AddCompositeType(...)
 
 	// Register all known API converters for the community AWS provider
+	acm.ValidationZoneID = *acmZoneID
+	acm.CertificateReferences = *acmReferences
+	route53.SetRegion(*route53Region)
+	for pc, r := range *route53PCRegions {
+		route53.SetProviderConfigRegion(pc, r)
+	}
+	rds.ConnectionSecretCompatibility = *rdsCompatibility
 	provideraws.RegisterAllKnownConverters(registry)
 
 	// Register ProviderConfigPreProcessor
@@ -128,6 +148,13 @@ func main() {
 	buff, err := yaml.Marshal(pg.Plan)
 	kingpin.FatalIfError(err, "Failed to marshal the migration plan into YAML")
 	kingpin.FatalIfError(os.WriteFile(*planPath, buff, 0600), "Failed to store the migration plan: %s", planPath)
+
+	// Write the warnings of the converters next to the migration plan
+	if warnings := common.Warnings(); len(warnings) > 0 {
+		warningsPath := filepath.Join(planDir, warningsFile)
+		kingpin.FatalIfError(os.WriteFile(warningsPath, []byte(strings.Join(warnings, "\n")+"\n"), 0600), "Failed to store the migration warnings: %s", warningsPath)
+		fmt.Printf("Stored %d migration warnings in %s\n", len(warnings), warningsPath)
+	}
 }
 
 func readSkipFile(path string) ([]schema.GroupVersionKind, error) {
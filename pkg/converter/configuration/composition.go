@@ -0,0 +1,161 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configuration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	pkgGroupSuffix = "pkg.crossplane.io"
+
+	fieldResources      = "spec.resources"
+	fieldDeletionPolicy = "spec.deletionPolicy"
+)
+
+// CompositionParameters patches the live Compositions that are not
+// managed by a Configuration package so that the managed resources they
+// compose from the migrated monolithic providers are orphaned during the
+// migration. Otherwise, the composite resources would keep on overwriting
+// the deletion policies of their orphaned managed resources with the
+// ones in their Compositions. The original Compositions are recorded so that
+// they can be restored once the migration has completed.
+type CompositionParameters struct {
+	// Monoliths are the names of the monolithic providers being migrated,
+	// such as provider-aws.
+	Monoliths []string
+
+	mu        sync.Mutex
+	originals map[string]unstructured.Unstructured
+}
+
+// Convert sets the deletion policies of the composed templates of the
+// monolithic providers being migrated to Orphan in the given Composition.
+func (cp *CompositionParameters) Convert(u *migration.UnstructuredWithMetadata) error {
+	if u.Metadata.Category != migration.CategoryComposition || isPackageManaged(u.Object) {
+		return nil
+	}
+	original := *u.Object.DeepCopy()
+	pv := fieldpath.Pave(u.Object.Object)
+	resources, err := pv.GetValue(fieldResources)
+	if err != nil {
+		if fieldpath.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get the composed templates of Composition: %s", u.Object.GetName())
+	}
+	rs, ok := resources.([]any)
+	if !ok {
+		return errors.Errorf("composed templates of Composition %s are not a list", u.Object.GetName())
+	}
+
+	changed := false
+	for i := range rs {
+		base := fmt.Sprintf("%s[%d].base", fieldResources, i)
+		apiVersion, err := pv.GetString(base + ".apiVersion")
+		if err != nil || !cp.isMigrated(apiVersion) {
+			continue
+		}
+		p, err := pv.GetString(fmt.Sprintf("%s.%s", base, fieldDeletionPolicy))
+		if err != nil && !fieldpath.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get the deletion policy of the composed template at index %d of Composition: %s", i, u.Object.GetName())
+		}
+		// the deletion policy defaults to Delete
+		if p != "" && xpv1.DeletionPolicy(p) != xpv1.DeletionDelete {
+			continue
+		}
+		if err := pv.SetString(fmt.Sprintf("%s.%s", base, fieldDeletionPolicy), string(xpv1.DeletionOrphan)); err != nil {
+			return errors.Wrapf(err, "failed to set the deletion policy of the composed template at index %d of Composition: %s", i, u.Object.GetName())
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if cp.originals == nil {
+		cp.originals = map[string]unstructured.Unstructured{}
+	}
+	cp.originals[u.Object.GetName()] = original
+	return nil
+}
+
+// RevertPatches returns the merge patches, sorted by the Composition names,
+// restoring the composed templates of the patched Compositions.
+func (cp *CompositionParameters) RevertPatches() ([]unstructured.Unstructured, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	names := make([]string, 0, len(cp.originals))
+	for n := range cp.originals {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	patches := make([]unstructured.Unstructured, 0, len(names))
+	for _, n := range names {
+		o := cp.originals[n]
+		resources, err := fieldpath.Pave(o.Object).GetValue(fieldResources)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the composed templates of Composition: %s", n)
+		}
+		p := unstructured.Unstructured{}
+		p.SetGroupVersionKind(o.GroupVersionKind())
+		p.SetName(n)
+		if err := fieldpath.Pave(p.Object).SetValue(fieldResources, resources); err != nil {
+			return nil, errors.Wrapf(err, "failed to set the composed templates of Composition: %s", n)
+		}
+		patches = append(patches, p)
+	}
+	return patches, nil
+}
+
+func (cp *CompositionParameters) isMigrated(apiVersion string) bool {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return false
+	}
+	for _, pn := range getProviderAndServiceName(gv.Group) {
+		for _, m := range cp.Monoliths {
+			if fmt.Sprintf("provider-%s", extractServiceProvider(pn)) == m {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPackageManaged returns true if the given object is owned by a Crossplane
+// package revision, in which case any changes to it would be reverted by the
+// package manager.
+func isPackageManaged(u unstructured.Unstructured) bool {
+	for _, o := range u.GetOwnerReferences() {
+		if strings.HasSuffix(strings.Split(o.APIVersion, "/")[0], pkgGroupSuffix) {
+			return true
+		}
+	}
+	return false
+}
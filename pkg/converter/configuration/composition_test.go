@@ -0,0 +1,153 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configuration
+
+import (
+	"testing"
+
+	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func composition(name string, owners []any, policies ...[2]string) migration.UnstructuredWithMetadata {
+	resources := make([]any, 0, len(policies))
+	for _, p := range policies {
+		spec := map[string]any{}
+		if p[1] != "" {
+			spec["deletionPolicy"] = p[1]
+		}
+		resources = append(resources, map[string]any{
+			"base": map[string]any{
+				"apiVersion": p[0],
+				"kind":       "Resource",
+				"spec":       spec,
+			},
+		})
+	}
+	metadata := map[string]any{
+		"name": name,
+	}
+	if owners != nil {
+		metadata["ownerReferences"] = owners
+	}
+	return migration.UnstructuredWithMetadata{
+		Object: unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "apiextensions.crossplane.io/v1",
+				"kind":       "Composition",
+				"metadata":   metadata,
+				"spec": map[string]any{
+					"resources": resources,
+				},
+			},
+		},
+		Metadata: migration.Metadata{
+			Category: migration.CategoryComposition,
+		},
+	}
+}
+
+func TestCompositionParametersConvert(t *testing.T) {
+	type want struct {
+		converted migration.UnstructuredWithMetadata
+		reverted  bool
+	}
+	cases := map[string]struct {
+		u    migration.UnstructuredWithMetadata
+		want want
+	}{
+		"OrphanMigratedProvider": {
+			u: composition("cluster", nil,
+				[2]string{"ec2.aws.upbound.io/v1beta1", "Delete"},
+				[2]string{"container.gcp.upbound.io/v1beta1", "Delete"},
+				[2]string{"eks.aws.upbound.io/v1beta1", ""}),
+			want: want{
+				converted: composition("cluster", nil,
+					[2]string{"ec2.aws.upbound.io/v1beta1", "Orphan"},
+					[2]string{"container.gcp.upbound.io/v1beta1", "Delete"},
+					[2]string{"eks.aws.upbound.io/v1beta1", "Orphan"}),
+				reverted: true,
+			},
+		},
+		"AlreadyOrphan": {
+			u: composition("cluster", nil,
+				[2]string{"ec2.aws.upbound.io/v1beta1", "Orphan"}),
+			want: want{
+				converted: composition("cluster", nil,
+					[2]string{"ec2.aws.upbound.io/v1beta1", "Orphan"}),
+			},
+		},
+		"PackageManaged": {
+			u: composition("cluster", []any{
+				map[string]any{
+					"apiVersion": "pkg.crossplane.io/v1",
+					"kind":       "ConfigurationRevision",
+					"name":       "platform-ref-aws-1234",
+					"uid":        "1234",
+				},
+			}, [2]string{"ec2.aws.upbound.io/v1beta1", "Delete"}),
+			want: want{
+				converted: composition("cluster", []any{
+					map[string]any{
+						"apiVersion": "pkg.crossplane.io/v1",
+						"kind":       "ConfigurationRevision",
+						"name":       "platform-ref-aws-1234",
+						"uid":        "1234",
+					},
+				}, [2]string{"ec2.aws.upbound.io/v1beta1", "Delete"}),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cp := &CompositionParameters{
+				Monoliths: []string{"provider-aws"},
+			}
+			original := *tc.u.Object.DeepCopy()
+			if err := cp.Convert(&tc.u); err != nil {
+				t.Fatalf("\nConvert(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.converted, tc.u); diff != "" {
+				t.Errorf("\nConvert(...): -want, +got:\n%s", diff)
+			}
+			patches, err := cp.RevertPatches()
+			if err != nil {
+				t.Fatalf("\nRevertPatches(): unexpected error: %v", err)
+			}
+			var want []unstructured.Unstructured
+			if tc.want.reverted {
+				want = []unstructured.Unstructured{
+					{
+						Object: map[string]any{
+							"apiVersion": "apiextensions.crossplane.io/v1",
+							"kind":       "Composition",
+							"metadata": map[string]any{
+								"name": "cluster",
+							},
+							"spec": map[string]any{
+								"resources": original.Object["spec"].(map[string]any)["resources"],
+							},
+						},
+					},
+				}
+			}
+			if diff := cmp.Diff(want, patches, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\nRevertPatches(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
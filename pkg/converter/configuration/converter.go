@@ -18,14 +18,16 @@ package configuration
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	xpmetav1alpha1 "github.com/crossplane/crossplane/apis/pkg/meta/v1alpha1"
 	xppkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
-	xppkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/upjet/pkg/migration"
 	"github.com/pkg/errors"
 )
@@ -35,51 +37,155 @@ const (
 )
 
 type mRPreProcessor struct {
-	ProviderNames map[string]struct{}
+	mu            sync.RWMutex
+	providerNames map[string]struct{}
 }
 
 func NewMRPreProcessor() *mRPreProcessor {
 	return &mRPreProcessor{
-		ProviderNames: map[string]struct{}{},
+		providerNames: map[string]struct{}{},
 	}
 }
 
 type compositionPreProcessor struct {
-	ProviderNames map[string]struct{}
+	mu            sync.RWMutex
+	providerNames map[string]struct{}
+	// packageRoots maps the root directories of the registered
+	// Configuration packages to their names.
+	packageRoots map[string]string
+	// packageProviderNames are the provider names collected from the
+	// Compositions of each registered Configuration package, keyed by the
+	// package name.
+	packageProviderNames map[string]map[string]struct{}
 }
 
 func NewCompositionPreProcessor() *compositionPreProcessor {
 	return &compositionPreProcessor{
-		ProviderNames: map[string]struct{}{},
+		providerNames:        map[string]struct{}{},
+		packageRoots:         map[string]string{},
+		packageProviderNames: map[string]map[string]struct{}{},
 	}
 }
 
+// AddPackage registers the Configuration package at the given root
+// directory, so that the provider names collected from its Compositions
+// are also kept separately for the package.
+func (cp *compositionPreProcessor) AddPackage(packageRoot string) error {
+	meta, err := readConfigurationMetadata(packageRoot)
+	if err != nil {
+		return err
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.packageRoots[filepath.Clean(packageRoot)] = meta.GetName()
+	if cp.packageProviderNames[meta.GetName()] == nil {
+		cp.packageProviderNames[meta.GetName()] = map[string]struct{}{}
+	}
+	return nil
+}
+
+// packageName returns the name of the Configuration package registered
+// with the given root directory.
+func (cp *compositionPreProcessor) packageName(packageRoot string) (string, bool) {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	name, ok := cp.packageRoots[filepath.Clean(packageRoot)]
+	return name, ok
+}
+
+// packageOf returns the name of the registered Configuration package
+// containing the file at the given path. If the package roots are nested,
+// the innermost package is returned.
+func (cp *compositionPreProcessor) packageOf(path string) (string, bool) {
+	path = filepath.Clean(path)
+	pkg, longest := "", -1
+	for root, name := range cp.packageRoots {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(root) > longest {
+			pkg, longest = name, len(root)
+		}
+	}
+	return pkg, longest >= 0
+}
+
 // GetSSOPNameFromManagedResource collects the new provider name from MR
 func (mp *mRPreProcessor) GetSSOPNameFromManagedResource(u migration.UnstructuredWithMetadata) error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
 	for _, pn := range getProviderAndServiceName(u.Object.GroupVersionKind().Group) {
-		mp.ProviderNames[pn] = struct{}{}
+		mp.providerNames[pn] = struct{}{}
 	}
 	return nil
 }
 
+// SortedProviderNames returns the collected provider names in
+// lexicographical order.
+func (mp *mRPreProcessor) SortedProviderNames() []string {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	return sortedKeys(mp.providerNames)
+}
+
 // GetSSOPNameFromComposition collects the new provider name from Composition
 func (cp *compositionPreProcessor) GetSSOPNameFromComposition(u migration.UnstructuredWithMetadata) error {
 	composition, err := migration.ToComposition(u.Object)
 	if err != nil {
 		return errors.Wrap(err, "unstructured object cannot be converted to composition")
 	}
+	var names []string
 	for _, composedTemplate := range composition.Spec.Resources {
 		composedUnstructured, err := migration.FromRawExtension(composedTemplate.Base)
 		if err != nil {
 			return errors.Wrap(err, "resource raw cannot convert to unstructured")
 		}
-		for _, pn := range getProviderAndServiceName(composedUnstructured.GroupVersionKind().Group) {
-			cp.ProviderNames[pn] = struct{}{}
-		}
+		names = append(names, getProviderAndServiceName(composedUnstructured.GroupVersionKind().Group)...)
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	pkgNames := map[string]struct{}{}
+	if pkg, ok := cp.packageOf(u.Metadata.Path); ok {
+		pkgNames = cp.packageProviderNames[pkg]
+	}
+	for _, pn := range names {
+		cp.providerNames[pn] = struct{}{}
+		pkgNames[pn] = struct{}{}
 	}
 	return nil
 }
 
+// SortedProviderNames returns the collected provider names in
+// lexicographical order.
+func (cp *compositionPreProcessor) SortedProviderNames() []string {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	return sortedKeys(cp.providerNames)
+}
+
+// SortedPackageProviderNames returns the provider names collected from
+// the Compositions of the given Configuration package in lexicographical
+// order. If no packages have been registered, all the collected provider
+// names are returned.
+func (cp *compositionPreProcessor) SortedPackageProviderNames(pkg string) []string {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	if len(cp.packageRoots) == 0 {
+		return sortedKeys(cp.providerNames)
+	}
+	return sortedKeys(cp.packageProviderNames[pkg])
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func getProviderAndServiceName(name string) []string {
 	parts := strings.Split(name, ".")
 	switch len(parts) {
@@ -96,24 +202,33 @@ type ConfigMetaParameters struct {
 	FamilyVersion        string
 	Monolith             string
 	CompositionProcessor *compositionPreProcessor
+	// ConfigurationMappings are used to rewrite the dependencies on the
+	// nested Configuration packages.
+	ConfigurationMappings []PackageMapping
 }
 
 type ConfigPkgParameters struct {
 	PackageURL string
 }
 
-type LockParameters struct {
-	PackageURL string
-}
-
 func (cm *ConfigMetaParameters) ConfigurationMetadataV1(c *xpmetav1.Configuration) error {
 	convertedList := make([]xpmetav1.Dependency, 0, len(c.Spec.DependsOn))
 	for _, provider := range c.Spec.DependsOn {
+		if provider.Configuration != nil {
+			if pkg, version, ok := convertConfigurationDependency(*provider.Configuration, cm.ConfigurationMappings); ok {
+				provider.Configuration = &pkg
+				if version != "" {
+					provider.Version = dependencyVersion(version)
+				}
+			}
+			convertedList = append(convertedList, provider)
+			continue
+		}
 		if provider.Provider != nil && *provider.Provider != fmt.Sprintf("xpkg.upbound.io/upbound/%s", cm.Monolith) {
 			convertedList = append(convertedList, provider)
 			continue
 		}
-		for providerName := range cm.CompositionProcessor.ProviderNames {
+		for _, providerName := range cm.CompositionProcessor.SortedPackageProviderNames(c.GetName()) {
 			if fmt.Sprintf("provider-%s", extractServiceProvider(providerName)) != cm.Monolith {
 				continue
 			}
@@ -133,6 +248,9 @@ func (cm *ConfigMetaParameters) ConfigurationMetadataV1(c *xpmetav1.Configuratio
 		}
 		found := false
 		for _, s := range convertedList {
+			if s.Provider == nil {
+				continue
+			}
 			if p != s && extractServiceProvider(extractProviderNameFromPackageName(*p.Provider)) == extractServiceProvider(extractProviderNameFromPackageName(*s.Provider)) {
 				found = true
 				break
@@ -145,6 +263,16 @@ func (cm *ConfigMetaParameters) ConfigurationMetadataV1(c *xpmetav1.Configuratio
 	return nil
 }
 
+// dependencyVersion returns the version constraint of a dependency on the
+// given package version. Digests are kept as they are because they cannot
+// be used in semantic version constraints.
+func dependencyVersion(version string) string {
+	if strings.Contains(version, ":") {
+		return version
+	}
+	return fmt.Sprintf(">=%s", version)
+}
+
 func extractServiceProvider(providerName string) string {
 	if strings.HasPrefix(providerName, prefixFamilyConfig) {
 		return strings.TrimPrefix(providerName, prefixFamilyConfig)
@@ -159,11 +287,21 @@ func extractServiceProvider(providerName string) string {
 func (cm *ConfigMetaParameters) ConfigurationMetadataV1Alpha1(c *xpmetav1alpha1.Configuration) error {
 	convertedList := make([]xpmetav1alpha1.Dependency, 0, len(c.Spec.DependsOn))
 	for _, provider := range c.Spec.DependsOn {
+		if provider.Configuration != nil {
+			if pkg, version, ok := convertConfigurationDependency(*provider.Configuration, cm.ConfigurationMappings); ok {
+				provider.Configuration = &pkg
+				if version != "" {
+					provider.Version = dependencyVersion(version)
+				}
+			}
+			convertedList = append(convertedList, provider)
+			continue
+		}
 		if provider.Provider != nil && *provider.Provider != fmt.Sprintf("xpkg.upbound.io/upbound/%s", cm.Monolith) {
 			convertedList = append(convertedList, provider)
 			continue
 		}
-		for providerName := range cm.CompositionProcessor.ProviderNames {
+		for _, providerName := range cm.CompositionProcessor.SortedPackageProviderNames(c.GetName()) {
 			if fmt.Sprintf("provider-%s", extractServiceProvider(providerName)) != cm.Monolith {
 				continue
 			}
@@ -183,6 +321,9 @@ func (cm *ConfigMetaParameters) ConfigurationMetadataV1Alpha1(c *xpmetav1alpha1.
 		}
 		found := false
 		for _, s := range convertedList {
+			if s.Provider == nil {
+				continue
+			}
 			if p != s && extractServiceProvider(extractProviderNameFromPackageName(*p.Provider)) == extractServiceProvider(extractProviderNameFromPackageName(*s.Provider)) {
 				found = true
 				break
@@ -200,18 +341,6 @@ func (cp *ConfigPkgParameters) ConfigurationPackageV1(pkg *xppkgv1.Configuration
 	return nil
 }
 
-func (l *LockParameters) PackageLockV1Beta1(lock *xppkgv1beta1.Lock) error {
-	packages := make([]xppkgv1beta1.LockPackage, 0, len(lock.Packages))
-	for _, lp := range lock.Packages {
-		if lp.Type == xppkgv1beta1.ConfigurationPackageType && strings.Join([]string{lp.Source, lp.Version}, ":") == l.PackageURL {
-			continue
-		}
-		packages = append(packages, lp)
-	}
-	lock.Packages = packages
-	return nil
-}
-
 type ProviderPkgFamilyConfigParameters struct {
 	FamilyVersion string
 }
@@ -249,15 +378,7 @@ type ProviderPkgFamilyParameters struct {
 func (pf *ProviderPkgFamilyParameters) ProviderPackageV1(p xppkgv1.Provider) ([]xppkgv1.Provider, error) {
 	ap := xppkgv1.ManualActivation
 	var providers []xppkgv1.Provider
-	var processorMap map[string]struct{}
-
-	if pf.CompositionProcessor != nil {
-		processorMap = pf.CompositionProcessor.ProviderNames
-	} else {
-		processorMap = pf.ManagedResourceProcessor.ProviderNames
-	}
-
-	for providerName := range processorMap {
+	for _, providerName := range pf.providerNames() {
 		if fmt.Sprintf("provider-%s", extractServiceProvider(providerName)) != pf.Monolith {
 			continue
 		}
@@ -289,6 +410,23 @@ func (pf *ProviderPkgFamilyParameters) ProviderPackageV1(p xppkgv1.Provider) ([]
 	return providers, nil
 }
 
+// providerNames returns the union of the provider names collected by the
+// configured pre-processors in lexicographical order.
+func (pf *ProviderPkgFamilyParameters) providerNames() []string {
+	names := map[string]struct{}{}
+	if pf.CompositionProcessor != nil {
+		for _, n := range pf.CompositionProcessor.SortedProviderNames() {
+			names[n] = struct{}{}
+		}
+	}
+	if pf.ManagedResourceProcessor != nil {
+		for _, n := range pf.ManagedResourceProcessor.SortedProviderNames() {
+			names[n] = struct{}{}
+		}
+	}
+	return sortedKeys(names)
+}
+
 func ptrFromString(s string) *string {
 	return &s
 }
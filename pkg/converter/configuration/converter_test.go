@@ -15,14 +15,12 @@
 package configuration
 
 import (
-	"sort"
-	"strings"
+	"sync"
 	"testing"
 
 	xpmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	xpmetav1alpha1 "github.com/crossplane/crossplane/apis/pkg/meta/v1alpha1"
 	xppkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
-	xppkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/crossplane/upjet/pkg/migration"
@@ -196,13 +194,53 @@ func TestGetSSOPNameFromManagedResource(t *testing.T) {
 			if diff := cmp.Diff(tc.want.err, err); diff != "" {
 				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
 			}
-			if diff := cmp.Diff(tc.want.providerNames, mp.ProviderNames); diff != "" {
+			if diff := cmp.Diff(tc.want.providerNames, mp.providerNames); diff != "" {
 				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
 			}
 		})
 	}
 }
 
+func TestGetSSOPNameFromManagedResourceConcurrent(t *testing.T) {
+	objects := []map[string]interface{}{
+		unstructuredAwsVpc,
+		unstructuredAwsProviderConfig,
+		unstructuredAzureZone,
+		unstructuredGcpZone,
+	}
+	mp := NewMRPreProcessor()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, o := range objects {
+			wg.Add(1)
+			go func(o map[string]interface{}) {
+				defer wg.Done()
+				u := migration.UnstructuredWithMetadata{
+					Object: unstructured.Unstructured{
+						Object: o,
+					},
+				}
+				if err := mp.GetSSOPNameFromManagedResource(u); err != nil {
+					t.Errorf("\nGetSSOPNameFromManagedResource(...): unexpected error: %v", err)
+				}
+			}(o)
+		}
+	}
+	wg.Wait()
+
+	want := []string{
+		"provider-aws-ec2",
+		"provider-azure-network",
+		"provider-family-aws",
+		"provider-family-azure",
+		"provider-family-gcp",
+		"provider-gcp-network",
+	}
+	if diff := cmp.Diff(want, mp.SortedProviderNames()); diff != "" {
+		t.Errorf("\nSortedProviderNames(...): -want, +got:\n%s", diff)
+	}
+}
+
 func TestConfigurationMetadataV1(t *testing.T) {
 	type args struct {
 		c *xpmetav1.Configuration
@@ -284,12 +322,50 @@ func TestConfigurationMetadataV1(t *testing.T) {
 				},
 			},
 		},
+		"WithFamilyProviderAndConfiguration": {
+			args: args{
+				c: &xpmetav1.Configuration{
+					Spec: xpmetav1.ConfigurationSpec{
+						MetaSpec: xpmetav1.MetaSpec{
+							DependsOn: []xpmetav1.Dependency{
+								{
+									Provider: ptrFromString("xpkg.upbound.io/upbound/provider-family-aws"),
+									Version:  ">=v0.33.0",
+								},
+								{
+									Configuration: ptrFromString("xpkg.upbound.io/upbound/platform-ref-aws-net"),
+									Version:       ">=v0.1.0",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				c: &xpmetav1.Configuration{
+					Spec: xpmetav1.ConfigurationSpec{
+						MetaSpec: xpmetav1.MetaSpec{
+							DependsOn: []xpmetav1.Dependency{
+								{
+									Provider: ptrFromString("xpkg.upbound.io/upbound/provider-family-aws"),
+									Version:  ">=v0.33.0",
+								},
+								{
+									Configuration: ptrFromString("xpkg.upbound.io/upbound/platform-ref-aws-net"),
+									Version:       ">=v0.1.0",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			cp := NewCompositionPreProcessor()
-			cp.ProviderNames = map[string]struct{}{
+			cp.providerNames = map[string]struct{}{
 				"provider-family-aws": {},
 				"provider-aws-ec2":    {},
 			}
@@ -302,9 +378,6 @@ func TestConfigurationMetadataV1(t *testing.T) {
 			if diff := cmp.Diff(tc.want.err, err); diff != "" {
 				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
 			}
-			sort.Slice(tc.args.c.Spec.DependsOn, func(i, j int) bool {
-				return strings.Compare(*tc.args.c.Spec.DependsOn[i].Provider, *tc.args.c.Spec.DependsOn[j].Provider) == -1
-			})
 			if diff := cmp.Diff(tc.want.c, tc.args.c); diff != "" {
 				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
 			}
@@ -393,12 +466,50 @@ func TestConfigurationMetadataV1Alpha(t *testing.T) {
 				},
 			},
 		},
+		"WithFamilyProviderAndConfiguration": {
+			args: args{
+				c: &xpmetav1alpha1.Configuration{
+					Spec: xpmetav1alpha1.ConfigurationSpec{
+						MetaSpec: xpmetav1alpha1.MetaSpec{
+							DependsOn: []xpmetav1alpha1.Dependency{
+								{
+									Provider: ptrFromString("xpkg.upbound.io/upbound/provider-family-aws"),
+									Version:  ">=v0.33.0",
+								},
+								{
+									Configuration: ptrFromString("xpkg.upbound.io/upbound/platform-ref-aws-net"),
+									Version:       ">=v0.1.0",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				c: &xpmetav1alpha1.Configuration{
+					Spec: xpmetav1alpha1.ConfigurationSpec{
+						MetaSpec: xpmetav1alpha1.MetaSpec{
+							DependsOn: []xpmetav1alpha1.Dependency{
+								{
+									Provider: ptrFromString("xpkg.upbound.io/upbound/provider-family-aws"),
+									Version:  ">=v0.33.0",
+								},
+								{
+									Configuration: ptrFromString("xpkg.upbound.io/upbound/platform-ref-aws-net"),
+									Version:       ">=v0.1.0",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			cp := NewCompositionPreProcessor()
-			cp.ProviderNames = map[string]struct{}{
+			cp.providerNames = map[string]struct{}{
 				"provider-family-aws": {},
 				"provider-aws-ec2":    {},
 			}
@@ -411,9 +522,6 @@ func TestConfigurationMetadataV1Alpha(t *testing.T) {
 			if diff := cmp.Diff(tc.want.err, err); diff != "" {
 				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
 			}
-			sort.Slice(tc.args.c.Spec.DependsOn, func(i, j int) bool {
-				return strings.Compare(*tc.args.c.Spec.DependsOn[i].Provider, *tc.args.c.Spec.DependsOn[j].Provider) == -1
-			})
 			if diff := cmp.Diff(tc.want.c, tc.args.c); diff != "" {
 				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
 			}
@@ -473,77 +581,6 @@ func TestConfigurationPackageV1(t *testing.T) {
 	}
 }
 
-func TestPackageLockV1Beta1(t *testing.T) {
-	type args struct {
-		lock *xppkgv1beta1.Lock
-	}
-	type want struct {
-		lock *xppkgv1beta1.Lock
-		err  error
-	}
-
-	cases := map[string]struct {
-		args
-		want
-	}{
-		"NeedRemoval": {
-			args: args{
-				lock: &xppkgv1beta1.Lock{
-					Packages: []xppkgv1beta1.LockPackage{
-						{
-							Source: awsPackage,
-						},
-					},
-				},
-			},
-			want: want{
-				lock: &xppkgv1beta1.Lock{
-					Packages: []xppkgv1beta1.LockPackage{
-						{
-							Source: "xpkg.upbound.io/upbound/provider-aws",
-						},
-					},
-				},
-				err: nil,
-			},
-		},
-		"NoNeedRemoval": {
-			args: args{
-				lock: &xppkgv1beta1.Lock{
-					Packages: []xppkgv1beta1.LockPackage{
-						{
-							Source: "xpkg.upbound.io/upbound/provider-helm",
-						},
-					},
-				},
-			},
-			want: want{
-				lock: &xppkgv1beta1.Lock{
-					Packages: []xppkgv1beta1.LockPackage{
-						{
-							Source: "xpkg.upbound.io/upbound/provider-helm",
-						},
-					},
-				},
-				err: nil,
-			},
-		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			l := LockParameters{}
-			err := l.PackageLockV1Beta1(tc.args.lock)
-			if diff := cmp.Diff(tc.want.err, err); diff != "" {
-				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
-			}
-			if diff := cmp.Diff(tc.want.lock, tc.args.lock); diff != "" {
-				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
-			}
-		})
-	}
-}
-
 func TestPackagePkgFamilyConfigParameters_ProviderPackageV1(t *testing.T) {
 	type args struct {
 		p xppkgv1.Provider
@@ -664,7 +701,7 @@ func TestPackagePkgFamilyParameters_ProviderPackageV1(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			cp := NewCompositionPreProcessor()
-			cp.ProviderNames = map[string]struct{}{
+			cp.providerNames = map[string]struct{}{
 				"provider-family-aws": {},
 				"provider-aws-ec2":    {},
 				"provider-aws-eks":    {},
@@ -678,12 +715,50 @@ func TestPackagePkgFamilyParameters_ProviderPackageV1(t *testing.T) {
 			if diff := cmp.Diff(tc.want.err, err); diff != "" {
 				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
 			}
-			sort.Slice(providers, func(i, j int) bool {
-				return strings.Compare(providers[i].Spec.PackageSpec.Package, providers[j].Spec.PackageSpec.Package) == -1
-			})
 			if diff := cmp.Diff(tc.want.providers, providers); diff != "" {
 				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestPackagePkgFamilyParameters_ProviderPackageV1ManagedAndComposition(t *testing.T) {
+	cp := NewCompositionPreProcessor()
+	cp.providerNames = map[string]struct{}{
+		"provider-family-aws": {},
+		"provider-aws-eks":    {},
+	}
+	mp := NewMRPreProcessor()
+	mp.providerNames = map[string]struct{}{
+		"provider-family-aws": {},
+		"provider-aws-ec2":    {},
+		"provider-aws-eks":    {},
+	}
+	pc := ProviderPkgFamilyParameters{
+		FamilyVersion:            "v0.37.0",
+		Monolith:                 "provider-aws",
+		CompositionProcessor:     cp,
+		ManagedResourceProcessor: mp,
+	}
+	providers, err := pc.ProviderPackageV1(xppkgv1.Provider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "provider-aws",
+		},
+		Spec: xppkgv1.ProviderSpec{
+			PackageSpec: xppkgv1.PackageSpec{
+				Package: "xpkg.upbound.io/upbound/provider-aws:v0.33.0",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("\nProviderPackageV1(...): unexpected error: %v", err)
+	}
+	want := []string{"upbound-provider-aws-ec2", "upbound-provider-aws-eks"}
+	got := make([]string, 0, len(providers))
+	for _, p := range providers {
+		got = append(got, p.GetName())
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nProviderPackageV1(...): -want, +got:\n%s", diff)
+	}
+}
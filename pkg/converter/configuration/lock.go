@@ -0,0 +1,170 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configuration
+
+import (
+	"fmt"
+	"strings"
+
+	xppkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	defaultRegistry = "xpkg.upbound.io"
+)
+
+// LockParameters converts the Crossplane package lock for the family
+// migration. The lock entry of the migration source Configuration package is
+// always removed. If a Monolith is configured, its lock entry is also removed
+// and the dependencies of the remaining packages on the monolith are rewritten
+// to the family providers collected by the configured pre-processor.
+type LockParameters struct {
+	// PackageURL is the URL of the migration source Configuration package.
+	// It may be specified with a tag, a digest or without a version, in
+	// which case all versions of the package match.
+	PackageURL string
+
+	FamilyVersion            string
+	Monolith                 string
+	CompositionProcessor     *compositionPreProcessor
+	ManagedResourceProcessor *mRPreProcessor
+	// ConfigurationMappings are used to find the package roots of the
+	// nested Configuration packages in the lock, whose dependencies are
+	// rewritten to the family providers of their own Compositions.
+	ConfigurationMappings []PackageMapping
+}
+
+func (l *LockParameters) PackageLockV1Beta1(lock *xppkgv1beta1.Lock) error {
+	packages := make([]xppkgv1beta1.LockPackage, 0, len(lock.Packages))
+	for _, lp := range lock.Packages {
+		if lp.Type == xppkgv1beta1.ConfigurationPackageType && matchesPackageURL(lp, l.PackageURL) {
+			continue
+		}
+		if l.Monolith != "" && lp.Type == xppkgv1beta1.ProviderPackageType && lp.Source == l.monolithSource() {
+			continue
+		}
+		if l.Monolith != "" {
+			lp.Dependencies = l.convertDependencies(lp.Dependencies, l.providerNames(lp))
+		}
+		packages = append(packages, lp)
+	}
+	lock.Packages = packages
+	return nil
+}
+
+// convertDependencies replaces the dependencies on the monolith with the
+// dependencies on the service-scoped providers of the same family. The family
+// config provider is only kept if there is no service-scoped provider of the
+// family as it is resolved as a dependency of the service-scoped providers.
+func (l *LockParameters) convertDependencies(deps []xppkgv1beta1.Dependency, providerNames []string) []xppkgv1beta1.Dependency {
+	if deps == nil {
+		return nil
+	}
+	converted := make([]xppkgv1beta1.Dependency, 0, len(deps))
+	replaced := false
+	for _, d := range deps {
+		if d.Type != xppkgv1beta1.ProviderPackageType || d.Package != l.monolithSource() {
+			converted = append(converted, d)
+			continue
+		}
+		if replaced {
+			continue
+		}
+		replaced = true
+		converted = append(converted, l.familyDependencies(providerNames)...)
+	}
+	return converted
+}
+
+// providerNames returns the union of the provider names collected by the
+// configured pre-processors for the given lock package in lexicographical
+// order. If the package root of the lock package is known, only the
+// provider names collected from its own Compositions are used.
+func (l *LockParameters) providerNames(lp xppkgv1beta1.LockPackage) []string {
+	names := map[string]struct{}{}
+	if l.CompositionProcessor != nil {
+		pkgNames := l.CompositionProcessor.SortedProviderNames()
+		if m, ok := findPackageMapping(lp.Source, l.ConfigurationMappings); ok && m.PackageRoot != "" {
+			if pkg, ok := l.CompositionProcessor.packageName(m.PackageRoot); ok {
+				pkgNames = l.CompositionProcessor.SortedPackageProviderNames(pkg)
+			}
+		}
+		for _, n := range pkgNames {
+			names[n] = struct{}{}
+		}
+	}
+	if l.ManagedResourceProcessor != nil {
+		for _, n := range l.ManagedResourceProcessor.SortedProviderNames() {
+			names[n] = struct{}{}
+		}
+	}
+	return sortedKeys(names)
+}
+
+func (l *LockParameters) familyDependencies(providerNames []string) []xppkgv1beta1.Dependency {
+	var service, family []xppkgv1beta1.Dependency
+	for _, providerName := range providerNames {
+		if fmt.Sprintf("provider-%s", extractServiceProvider(providerName)) != l.Monolith {
+			continue
+		}
+		d := xppkgv1beta1.Dependency{
+			Package:     fmt.Sprintf("%s/upbound/%s", defaultRegistry, providerName),
+			Type:        xppkgv1beta1.ProviderPackageType,
+			Constraints: fmt.Sprintf(">=%s", l.FamilyVersion),
+		}
+		if strings.HasPrefix(providerName, prefixFamilyConfig) {
+			family = append(family, d)
+			continue
+		}
+		service = append(service, d)
+	}
+	if len(service) == 0 {
+		return family
+	}
+	return service
+}
+
+func (l *LockParameters) monolithSource() string {
+	return fmt.Sprintf("%s/upbound/%s", defaultRegistry, l.Monolith)
+}
+
+// matchesPackageURL reports whether the given lock package is the package
+// referred to by the given URL. The URL matches all versions of the package
+// if it has neither a tag nor a digest.
+func matchesPackageURL(lp xppkgv1beta1.LockPackage, url string) bool {
+	source, version := parsePackageURL(url)
+	if source == "" || lp.Source != source {
+		return false
+	}
+	return version == "" || lp.Version == version
+}
+
+// parsePackageURL splits the given package URL into its source and version.
+// The version is either a tag or a digest and is empty if the URL has
+// neither. The source is qualified with the default registry if the URL does
+// not specify a registry host.
+func parsePackageURL(url string) (string, string) {
+	source, version := url, ""
+	if i := strings.LastIndex(url, "@"); i != -1 {
+		source, version = url[:i], url[i+1:]
+	} else if i := strings.LastIndex(url, ":"); i != -1 && !strings.Contains(url[i:], "/") {
+		source, version = url[:i], url[i+1:]
+	}
+	parts := strings.SplitN(source, "/", 2)
+	if len(parts) == 2 && !strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost" {
+		source = fmt.Sprintf("%s/%s", defaultRegistry, source)
+	}
+	return source, version
+}
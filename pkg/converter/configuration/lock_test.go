@@ -0,0 +1,325 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	xppkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	exampleConfigurationPackage = "index.docker.io/ezgid/test-smaller-provider-migration"
+)
+
+func readLock(t *testing.T, name string) *xppkgv1beta1.Lock {
+	t.Helper()
+	buff, err := os.ReadFile(filepath.Join("..", "..", "..", "docs", name))
+	if err != nil {
+		t.Fatalf("Failed to read the example lock %s: %v", name, err)
+	}
+	lock := &xppkgv1beta1.Lock{}
+	if err := yaml.Unmarshal(buff, lock); err != nil {
+		t.Fatalf("Failed to unmarshal the example lock %s: %v", name, err)
+	}
+	return lock
+}
+
+func TestPackageLockV1Beta1(t *testing.T) {
+	type args struct {
+		lock *xppkgv1beta1.Lock
+	}
+	type want struct {
+		lock *xppkgv1beta1.Lock
+		err  error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NeedRemoval": {
+			args: args{
+				lock: &xppkgv1beta1.Lock{
+					Packages: []xppkgv1beta1.LockPackage{
+						{
+							Source: awsPackage,
+						},
+					},
+				},
+			},
+			want: want{
+				lock: &xppkgv1beta1.Lock{
+					Packages: []xppkgv1beta1.LockPackage{
+						{
+							Source: "xpkg.upbound.io/upbound/provider-aws",
+						},
+					},
+				},
+				err: nil,
+			},
+		},
+		"NoNeedRemoval": {
+			args: args{
+				lock: &xppkgv1beta1.Lock{
+					Packages: []xppkgv1beta1.LockPackage{
+						{
+							Source: "xpkg.upbound.io/upbound/provider-helm",
+						},
+					},
+				},
+			},
+			want: want{
+				lock: &xppkgv1beta1.Lock{
+					Packages: []xppkgv1beta1.LockPackage{
+						{
+							Source: "xpkg.upbound.io/upbound/provider-helm",
+						},
+					},
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			l := LockParameters{}
+			err := l.PackageLockV1Beta1(tc.args.lock)
+			if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.lock, tc.args.lock); diff != "" {
+				t.Errorf("\nNext(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPackageLockV1Beta1ExampleLock(t *testing.T) {
+	withoutMonolith := readLock(t, "example-lock-after.yaml")
+	withoutMonolith.Packages = []xppkgv1beta1.LockPackage{}
+
+	cases := map[string]struct {
+		l    LockParameters
+		want *xppkgv1beta1.Lock
+	}{
+		"Tag": {
+			l: LockParameters{
+				PackageURL: exampleConfigurationPackage + ":v0.4.0",
+			},
+			want: readLock(t, "example-lock-after.yaml"),
+		},
+		"MissingTag": {
+			l: LockParameters{
+				PackageURL: exampleConfigurationPackage,
+			},
+			want: readLock(t, "example-lock-after.yaml"),
+		},
+		"OtherVersion": {
+			l: LockParameters{
+				PackageURL: exampleConfigurationPackage + ":v0.5.0",
+			},
+			want: readLock(t, "example-lock-before.yaml"),
+		},
+		"PruneMonolith": {
+			l: LockParameters{
+				PackageURL: exampleConfigurationPackage + ":v0.4.0",
+				Monolith:   "provider-gcp",
+			},
+			want: withoutMonolith,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			lock := readLock(t, "example-lock-before.yaml")
+			if err := tc.l.PackageLockV1Beta1(lock); err != nil {
+				t.Fatalf("\nPackageLockV1Beta1(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, lock); diff != "" {
+				t.Errorf("\nPackageLockV1Beta1(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPackageLockV1Beta1Dependencies(t *testing.T) {
+	cp := NewCompositionPreProcessor()
+	cp.providerNames = map[string]struct{}{
+		"provider-family-gcp":  {},
+		"provider-gcp-storage": {},
+		"provider-gcp-compute": {},
+		"provider-family-aws":  {},
+		"provider-aws-ec2":     {},
+	}
+	l := LockParameters{
+		PackageURL:           exampleConfigurationPackage + ":v0.4.0",
+		FamilyVersion:        "v0.37.0",
+		Monolith:             "provider-gcp",
+		CompositionProcessor: cp,
+	}
+	lock := &xppkgv1beta1.Lock{
+		Packages: []xppkgv1beta1.LockPackage{
+			{
+				Name:    "ezgid-test-smaller-provider-migration-3a0d5472dd78",
+				Source:  exampleConfigurationPackage,
+				Type:    xppkgv1beta1.ConfigurationPackageType,
+				Version: "v0.4.0",
+				Dependencies: []xppkgv1beta1.Dependency{
+					{
+						Package:     "xpkg.upbound.io/upbound/provider-gcp",
+						Type:        xppkgv1beta1.ProviderPackageType,
+						Constraints: ">=v0.31.0",
+					},
+				},
+			},
+			{
+				Name:    "upbound-platform-ref-gcp-net",
+				Source:  "xpkg.upbound.io/upbound/platform-ref-gcp-net",
+				Type:    xppkgv1beta1.ConfigurationPackageType,
+				Version: "v0.1.0",
+				Dependencies: []xppkgv1beta1.Dependency{
+					{
+						Package:     "xpkg.upbound.io/upbound/provider-gcp",
+						Type:        xppkgv1beta1.ProviderPackageType,
+						Constraints: ">=v0.31.0",
+					},
+					{
+						Package:     "xpkg.upbound.io/upbound/provider-helm",
+						Type:        xppkgv1beta1.ProviderPackageType,
+						Constraints: ">=v0.15.0",
+					},
+				},
+			},
+			{
+				Name:         "upbound-provider-gcp-a206c0fc297b",
+				Source:       "xpkg.upbound.io/upbound/provider-gcp",
+				Type:         xppkgv1beta1.ProviderPackageType,
+				Version:      "v0.32.0",
+				Dependencies: []xppkgv1beta1.Dependency{},
+			},
+			{
+				Name:         "upbound-provider-helm-0d4c7a19e4f1",
+				Source:       "xpkg.upbound.io/upbound/provider-helm",
+				Type:         xppkgv1beta1.ProviderPackageType,
+				Version:      "v0.15.0",
+				Dependencies: []xppkgv1beta1.Dependency{},
+			},
+		},
+	}
+	want := &xppkgv1beta1.Lock{
+		Packages: []xppkgv1beta1.LockPackage{
+			{
+				Name:    "upbound-platform-ref-gcp-net",
+				Source:  "xpkg.upbound.io/upbound/platform-ref-gcp-net",
+				Type:    xppkgv1beta1.ConfigurationPackageType,
+				Version: "v0.1.0",
+				Dependencies: []xppkgv1beta1.Dependency{
+					{
+						Package:     "xpkg.upbound.io/upbound/provider-gcp-compute",
+						Type:        xppkgv1beta1.ProviderPackageType,
+						Constraints: ">=v0.37.0",
+					},
+					{
+						Package:     "xpkg.upbound.io/upbound/provider-gcp-storage",
+						Type:        xppkgv1beta1.ProviderPackageType,
+						Constraints: ">=v0.37.0",
+					},
+					{
+						Package:     "xpkg.upbound.io/upbound/provider-helm",
+						Type:        xppkgv1beta1.ProviderPackageType,
+						Constraints: ">=v0.15.0",
+					},
+				},
+			},
+			{
+				Name:         "upbound-provider-helm-0d4c7a19e4f1",
+				Source:       "xpkg.upbound.io/upbound/provider-helm",
+				Type:         xppkgv1beta1.ProviderPackageType,
+				Version:      "v0.15.0",
+				Dependencies: []xppkgv1beta1.Dependency{},
+			},
+		},
+	}
+	if err := l.PackageLockV1Beta1(lock); err != nil {
+		t.Fatalf("\nPackageLockV1Beta1(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, lock); diff != "" {
+		t.Errorf("\nPackageLockV1Beta1(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestParsePackageURL(t *testing.T) {
+	type want struct {
+		source  string
+		version string
+	}
+
+	cases := map[string]struct {
+		url string
+		want
+	}{
+		"Tag": {
+			url: "xpkg.upbound.io/upbound/platform-ref-aws:v0.6.0",
+			want: want{
+				source:  "xpkg.upbound.io/upbound/platform-ref-aws",
+				version: "v0.6.0",
+			},
+		},
+		"Digest": {
+			url: "xpkg.upbound.io/upbound/platform-ref-aws@sha256:0123",
+			want: want{
+				source:  "xpkg.upbound.io/upbound/platform-ref-aws",
+				version: "sha256:0123",
+			},
+		},
+		"MissingTag": {
+			url: "xpkg.upbound.io/upbound/platform-ref-aws",
+			want: want{
+				source: "xpkg.upbound.io/upbound/platform-ref-aws",
+			},
+		},
+		"RegistryPort": {
+			url: "localhost:5000/upbound/platform-ref-aws",
+			want: want{
+				source: "localhost:5000/upbound/platform-ref-aws",
+			},
+		},
+		"MissingRegistry": {
+			url: "upbound/platform-ref-aws:v0.6.0",
+			want: want{
+				source:  "xpkg.upbound.io/upbound/platform-ref-aws",
+				version: "v0.6.0",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			source, version := parsePackageURL(tc.url)
+			if diff := cmp.Diff(tc.want.source, source); diff != "" {
+				t.Errorf("\nparsePackageURL(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.version, version); diff != "" {
+				t.Errorf("\nparsePackageURL(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
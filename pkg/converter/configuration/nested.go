@@ -0,0 +1,168 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	xpmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	xppkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	packageMetaFile = "crossplane.yaml"
+)
+
+// PackageMapping maps a migration source Configuration package to its
+// migration target.
+type PackageMapping struct {
+	// Source is the URL of the migration source Configuration package.
+	Source string `json:"source"`
+	// Target is the URL of the migration target Configuration package.
+	Target string `json:"target"`
+	// PackageRoot is the optional source directory of the Configuration
+	// package. If it's set, the Compositions and the dependencies of the
+	// package are also processed.
+	PackageRoot string `json:"packageRoot,omitempty"`
+}
+
+// ReadPackageMappings reads the Configuration package mappings from the
+// YAML file at the given path.
+func ReadPackageMappings(path string) ([]PackageMapping, error) {
+	buff, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the package mapping file: %s", path)
+	}
+	var mappings []PackageMapping
+	if err := yaml.Unmarshal(buff, &mappings); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the package mapping file: %s", path)
+	}
+	for i, m := range mappings {
+		if m.Source == "" || m.Target == "" {
+			return nil, errors.Errorf("package mapping at index %d in file %s must specify both source and target", i, path)
+		}
+	}
+	return mappings, nil
+}
+
+// NestedConfigurations walks the Configuration dependency tree of the given
+// root package and returns the mappings of all the nested Configuration
+// packages found, in a breadth-first order. The dependencies of a package are
+// collected both from its entry in the given package lock, which may be nil,
+// and from the dependsOn of its metadata if the package root is known. An
+// error is returned if there's no mapping for a nested Configuration.
+func NestedConfigurations(root PackageMapping, lock *xppkgv1beta1.Lock, mappings []PackageMapping) ([]PackageMapping, error) {
+	var nested []PackageMapping
+	visited := map[string]struct{}{}
+	rootSource, _ := parsePackageURL(root.Source)
+	visited[rootSource] = struct{}{}
+	queue := []PackageMapping{root}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		deps, err := configurationDependencies(parent, lock)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range deps {
+			if _, ok := visited[d]; ok {
+				continue
+			}
+			visited[d] = struct{}{}
+			m, ok := findPackageMapping(d, mappings)
+			if !ok {
+				return nil, errors.Errorf("no package mapping found for the nested Configuration %q of package %q", d, parent.Source)
+			}
+			nested = append(nested, m)
+			queue = append(queue, m)
+		}
+	}
+	return nested, nil
+}
+
+// configurationDependencies returns the sorted sources of the Configuration
+// packages the given package depends on.
+func configurationDependencies(m PackageMapping, lock *xppkgv1beta1.Lock) ([]string, error) {
+	deps := map[string]struct{}{}
+	if lock != nil {
+		for _, lp := range lock.Packages {
+			if lp.Type != xppkgv1beta1.ConfigurationPackageType || !matchesPackageURL(lp, m.Source) {
+				continue
+			}
+			for _, d := range lp.Dependencies {
+				if d.Type == xppkgv1beta1.ConfigurationPackageType {
+					source, _ := parsePackageURL(d.Package)
+					deps[source] = struct{}{}
+				}
+			}
+		}
+	}
+	if m.PackageRoot != "" {
+		meta, err := readConfigurationMetadata(m.PackageRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range meta.Spec.DependsOn {
+			if d.Configuration != nil {
+				source, _ := parsePackageURL(*d.Configuration)
+				deps[source] = struct{}{}
+			}
+		}
+	}
+	return sortedKeys(deps), nil
+}
+
+func readConfigurationMetadata(packageRoot string) (*xpmetav1.Configuration, error) {
+	path := filepath.Join(packageRoot, packageMetaFile)
+	buff, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the Configuration package metadata: %s", path)
+	}
+	meta := &xpmetav1.Configuration{}
+	if err := yaml.Unmarshal(buff, meta); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the Configuration package metadata: %s", path)
+	}
+	return meta, nil
+}
+
+func findPackageMapping(source string, mappings []PackageMapping) (PackageMapping, bool) {
+	for _, m := range mappings {
+		if s, _ := parsePackageURL(m.Source); s == source {
+			return m, true
+		}
+	}
+	return PackageMapping{}, false
+}
+
+// convertConfigurationDependency returns the target package and its version
+// for a Configuration dependency if there's a mapping for it.
+func convertConfigurationDependency(pkg string, mappings []PackageMapping) (string, string, bool) {
+	source, _ := parsePackageURL(pkg)
+	m, ok := findPackageMapping(source, mappings)
+	if !ok {
+		return "", "", false
+	}
+	target, version := parsePackageURL(m.Target)
+	// keep the package reference as it's specified in the metadata if the
+	// registry was omitted.
+	if !strings.HasPrefix(pkg, defaultRegistry+"/") {
+		target = strings.TrimPrefix(target, defaultRegistry+"/")
+	}
+	return target, version, true
+}
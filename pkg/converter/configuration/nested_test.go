@@ -0,0 +1,382 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	xpmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	xppkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const (
+	platformMeta = `apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: platform-ref-aws
+spec:
+  dependsOn:
+    - provider: xpkg.upbound.io/upbound/provider-aws
+      version: ">=v0.32.0"
+    - configuration: xpkg.upbound.io/upbound/platform-ref-aws-net
+      version: ">=v0.1.0"
+`
+	networkMeta = `apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: platform-ref-aws-net
+spec:
+  dependsOn:
+    - provider: xpkg.upbound.io/upbound/provider-aws
+      version: ">=v0.32.0"
+`
+)
+
+func writePackageMeta(t *testing.T, meta string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, packageMetaFile), []byte(meta), 0600); err != nil {
+		t.Fatalf("Failed to write the package metadata: %v", err)
+	}
+	return dir
+}
+
+func TestNestedConfigurations(t *testing.T) {
+	platformRoot := writePackageMeta(t, platformMeta)
+	networkRoot := writePackageMeta(t, networkMeta)
+
+	network := PackageMapping{
+		Source:      "xpkg.upbound.io/upbound/platform-ref-aws-net:v0.1.0",
+		Target:      "xpkg.upbound.io/upbound/platform-ref-aws-net:v0.2.0",
+		PackageRoot: networkRoot,
+	}
+	database := PackageMapping{
+		Source: "xpkg.upbound.io/upbound/platform-ref-aws-db:v0.1.0",
+		Target: "xpkg.upbound.io/upbound/platform-ref-aws-db:v0.2.0",
+	}
+	lock := &xppkgv1beta1.Lock{
+		Packages: []xppkgv1beta1.LockPackage{
+			{
+				Source:  "xpkg.upbound.io/upbound/platform-ref-aws",
+				Type:    xppkgv1beta1.ConfigurationPackageType,
+				Version: "v0.6.0",
+				Dependencies: []xppkgv1beta1.Dependency{
+					{
+						Package: "xpkg.upbound.io/upbound/platform-ref-aws-net",
+						Type:    xppkgv1beta1.ConfigurationPackageType,
+					},
+				},
+			},
+			{
+				Source:  "xpkg.upbound.io/upbound/platform-ref-aws-net",
+				Type:    xppkgv1beta1.ConfigurationPackageType,
+				Version: "v0.1.0",
+				Dependencies: []xppkgv1beta1.Dependency{
+					{
+						Package: "xpkg.upbound.io/upbound/platform-ref-aws-db",
+						Type:    xppkgv1beta1.ConfigurationPackageType,
+					},
+					{
+						Package: "xpkg.upbound.io/upbound/provider-aws",
+						Type:    xppkgv1beta1.ProviderPackageType,
+					},
+				},
+			},
+		},
+	}
+
+	type args struct {
+		root     PackageMapping
+		lock     *xppkgv1beta1.Lock
+		mappings []PackageMapping
+	}
+	type want struct {
+		nested []PackageMapping
+		err    error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"FromMetadata": {
+			args: args{
+				root: PackageMapping{
+					Source:      "xpkg.upbound.io/upbound/platform-ref-aws:v0.6.0",
+					PackageRoot: platformRoot,
+				},
+				mappings: []PackageMapping{network},
+			},
+			want: want{
+				nested: []PackageMapping{network},
+			},
+		},
+		"FromLockAndMetadata": {
+			args: args{
+				root: PackageMapping{
+					Source:      "xpkg.upbound.io/upbound/platform-ref-aws:v0.6.0",
+					PackageRoot: platformRoot,
+				},
+				lock:     lock,
+				mappings: []PackageMapping{database, network},
+			},
+			want: want{
+				nested: []PackageMapping{network, database},
+			},
+		},
+		"MissingMapping": {
+			args: args{
+				root: PackageMapping{
+					Source: "xpkg.upbound.io/upbound/platform-ref-aws:v0.6.0",
+				},
+				lock:     lock,
+				mappings: []PackageMapping{network},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"NoNestedConfiguration": {
+			args: args{
+				root: PackageMapping{
+					Source:      "xpkg.upbound.io/upbound/platform-ref-aws-net:v0.1.0",
+					PackageRoot: networkRoot,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			nested, err := NestedConfigurations(tc.args.root, tc.args.lock, tc.args.mappings)
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\nNestedConfigurations(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.nested, nested); diff != "" {
+				t.Errorf("\nNestedConfigurations(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigurationMetadataV1NestedConfiguration(t *testing.T) {
+	cp := NewCompositionPreProcessor()
+	cp.providerNames = map[string]struct{}{
+		"provider-family-aws": {},
+		"provider-aws-ec2":    {},
+	}
+	cm := ConfigMetaParameters{
+		Monolith:             "provider-aws",
+		FamilyVersion:        "v0.37.0",
+		CompositionProcessor: cp,
+		ConfigurationMappings: []PackageMapping{
+			{
+				Source: "xpkg.upbound.io/upbound/platform-ref-aws-net:v0.1.0",
+				Target: "xpkg.upbound.io/upbound/platform-ref-aws-net-ssop:v0.2.0",
+			},
+			{
+				Source: "xpkg.upbound.io/upbound/platform-ref-aws-app:v0.1.0",
+				Target: "xpkg.upbound.io/upbound/platform-ref-aws-app-ssop@sha256:0123",
+			},
+		},
+	}
+	c := &xpmetav1.Configuration{
+		Spec: xpmetav1.ConfigurationSpec{
+			MetaSpec: xpmetav1.MetaSpec{
+				DependsOn: []xpmetav1.Dependency{
+					{
+						Provider: ptrFromString("xpkg.upbound.io/upbound/provider-aws"),
+						Version:  ">=v0.32.0",
+					},
+					{
+						Configuration: ptrFromString("xpkg.upbound.io/upbound/platform-ref-aws-net"),
+						Version:       ">=v0.1.0",
+					},
+					{
+						Configuration: ptrFromString("xpkg.upbound.io/upbound/platform-ref-aws-db"),
+						Version:       ">=v0.1.0",
+					},
+					{
+						Configuration: ptrFromString("xpkg.upbound.io/upbound/platform-ref-aws-app"),
+						Version:       ">=v0.1.0",
+					},
+				},
+			},
+		},
+	}
+	want := &xpmetav1.Configuration{
+		Spec: xpmetav1.ConfigurationSpec{
+			MetaSpec: xpmetav1.MetaSpec{
+				DependsOn: []xpmetav1.Dependency{
+					{
+						Provider: ptrFromString("xpkg.upbound.io/upbound/provider-aws-ec2"),
+						Version:  ">=v0.37.0",
+					},
+					{
+						Configuration: ptrFromString("xpkg.upbound.io/upbound/platform-ref-aws-net-ssop"),
+						Version:       ">=v0.2.0",
+					},
+					{
+						Configuration: ptrFromString("xpkg.upbound.io/upbound/platform-ref-aws-db"),
+						Version:       ">=v0.1.0",
+					},
+					{
+						Configuration: ptrFromString("xpkg.upbound.io/upbound/platform-ref-aws-app-ssop"),
+						Version:       "sha256:0123",
+					},
+				},
+			},
+		},
+	}
+	if err := cm.ConfigurationMetadataV1(c); err != nil {
+		t.Fatalf("\nConfigurationMetadataV1(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, c); diff != "" {
+		t.Errorf("\nConfigurationMetadataV1(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestCompositionPreProcessorPackageProviderNames(t *testing.T) {
+	root := writePackageMeta(t, platformMeta)
+	net := filepath.Join(root, "network")
+	if err := os.Mkdir(net, 0700); err != nil {
+		t.Fatalf("Failed to create the nested package root: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(net, packageMetaFile), []byte(networkMeta), 0600); err != nil {
+		t.Fatalf("Failed to write the nested package metadata: %v", err)
+	}
+	cp := NewCompositionPreProcessor()
+	for _, r := range []string{root, net} {
+		if err := cp.AddPackage(r); err != nil {
+			t.Fatalf("\nAddPackage(%s): unexpected error: %v", r, err)
+		}
+	}
+	compositions := map[string]migration.UnstructuredWithMetadata{
+		filepath.Join(root, "cluster", "composition.yaml"): composition("cluster", nil,
+			[2]string{"eks.aws.upbound.io/v1beta1", ""}),
+		filepath.Join(net, "composition.yaml"): composition("network", nil,
+			[2]string{"ec2.aws.upbound.io/v1beta1", ""}),
+	}
+	for path, u := range compositions {
+		u.Metadata.Path = path
+		if err := cp.GetSSOPNameFromComposition(u); err != nil {
+			t.Fatalf("\nGetSSOPNameFromComposition(...): unexpected error: %v", err)
+		}
+	}
+	want := map[string][]string{
+		"platform-ref-aws":     {"provider-aws-eks", "provider-family-aws"},
+		"platform-ref-aws-net": {"provider-aws-ec2", "provider-family-aws"},
+	}
+	for pkg, names := range want {
+		if diff := cmp.Diff(names, cp.SortedPackageProviderNames(pkg)); diff != "" {
+			t.Errorf("\nSortedPackageProviderNames(%s): -want, +got:\n%s", pkg, diff)
+		}
+	}
+}
+
+func TestPackageLockV1Beta1NestedConfiguration(t *testing.T) {
+	root := writePackageMeta(t, platformMeta)
+	net := writePackageMeta(t, networkMeta)
+	cp := NewCompositionPreProcessor()
+	for _, r := range []string{root, net} {
+		if err := cp.AddPackage(r); err != nil {
+			t.Fatalf("\nAddPackage(%s): unexpected error: %v", r, err)
+		}
+	}
+	compositions := map[string]migration.UnstructuredWithMetadata{
+		filepath.Join(root, "composition.yaml"): composition("cluster", nil,
+			[2]string{"eks.aws.upbound.io/v1beta1", ""}),
+		filepath.Join(net, "composition.yaml"): composition("network", nil,
+			[2]string{"ec2.aws.upbound.io/v1beta1", ""}),
+	}
+	for path, u := range compositions {
+		u.Metadata.Path = path
+		if err := cp.GetSSOPNameFromComposition(u); err != nil {
+			t.Fatalf("\nGetSSOPNameFromComposition(...): unexpected error: %v", err)
+		}
+	}
+	l := LockParameters{
+		PackageURL:           "xpkg.upbound.io/upbound/platform-ref-aws:v0.6.0",
+		FamilyVersion:        "v0.37.0",
+		Monolith:             "provider-aws",
+		CompositionProcessor: cp,
+		ConfigurationMappings: []PackageMapping{
+			{
+				Source:      "xpkg.upbound.io/upbound/platform-ref-aws-net:v0.1.0",
+				Target:      "xpkg.upbound.io/upbound/platform-ref-aws-net-ssop:v0.2.0",
+				PackageRoot: net,
+			},
+		},
+	}
+	monolith := []xppkgv1beta1.Dependency{
+		{
+			Package:     "xpkg.upbound.io/upbound/provider-aws",
+			Type:        xppkgv1beta1.ProviderPackageType,
+			Constraints: ">=v0.32.0",
+		},
+	}
+	lock := &xppkgv1beta1.Lock{
+		Packages: []xppkgv1beta1.LockPackage{
+			{
+				Source:       "xpkg.upbound.io/upbound/platform-ref-aws-net",
+				Type:         xppkgv1beta1.ConfigurationPackageType,
+				Version:      "v0.1.0",
+				Dependencies: monolith,
+			},
+			{
+				Source:       "xpkg.upbound.io/upbound/platform-ref-aws-db",
+				Type:         xppkgv1beta1.ConfigurationPackageType,
+				Version:      "v0.1.0",
+				Dependencies: monolith,
+			},
+		},
+	}
+	dependency := func(name string) xppkgv1beta1.Dependency {
+		return xppkgv1beta1.Dependency{
+			Package:     "xpkg.upbound.io/upbound/" + name,
+			Type:        xppkgv1beta1.ProviderPackageType,
+			Constraints: ">=v0.37.0",
+		}
+	}
+	want := &xppkgv1beta1.Lock{
+		Packages: []xppkgv1beta1.LockPackage{
+			{
+				Source:       "xpkg.upbound.io/upbound/platform-ref-aws-net",
+				Type:         xppkgv1beta1.ConfigurationPackageType,
+				Version:      "v0.1.0",
+				Dependencies: []xppkgv1beta1.Dependency{dependency("provider-aws-ec2")},
+			},
+			{
+				// the package root of the package is not known, so it
+				// depends on the providers of all the Compositions.
+				Source:       "xpkg.upbound.io/upbound/platform-ref-aws-db",
+				Type:         xppkgv1beta1.ConfigurationPackageType,
+				Version:      "v0.1.0",
+				Dependencies: []xppkgv1beta1.Dependency{dependency("provider-aws-ec2"), dependency("provider-aws-eks")},
+			},
+		},
+	}
+	if err := l.PackageLockV1Beta1(lock); err != nil {
+		t.Fatalf("\nPackageLockV1Beta1(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, lock); diff != "" {
+		t.Errorf("\nPackageLockV1Beta1(...): -want, +got:\n%s", diff)
+	}
+}
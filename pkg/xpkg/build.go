@@ -0,0 +1,252 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package xpkg builds and publishes Crossplane packages without depending on
+// the external up or crossplane CLIs.
+package xpkg
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultRegistry is the registry used for the package references
+	// without a registry host.
+	DefaultRegistry = "xpkg.upbound.io"
+
+	// StreamFile is the name of the file in the package layer containing
+	// the package metadata and objects.
+	StreamFile = "package.yaml"
+	// ExamplesFile is the name of the file in the examples layer containing
+	// the package examples.
+	ExamplesFile = ".up/examples.yaml"
+
+	// AnnotationKey is the layer annotation and the config label prefix
+	// identifying the xpkg layers.
+	AnnotationKey = "io.crossplane.xpkg"
+	// PackageAnnotation is the annotation value of the package layer.
+	PackageAnnotation = "base"
+	// ExamplesAnnotation is the annotation value of the examples layer.
+	ExamplesAnnotation = "upbound"
+	// DefaultExamplesDir is the directory of the examples under the package
+	// root, which is not part of the package stream.
+	DefaultExamplesDir = "examples"
+
+	metaGroup      = "meta.pkg.crossplane.io"
+	streamFileMode = 0o644
+	yamlSeparator  = "---\n"
+)
+
+// Build builds a Crossplane package image from the YAML manifests under the
+// given package root. The package metadata is placed at the beginning of the
+// package stream. If the examples root is not empty, the YAML manifests under
+// it are placed in a separate examples layer. The examples root, or the
+// default examples directory if no examples root is given, is skipped while
+// reading the package root if it's nested under the package root.
+func Build(packageRoot, examplesRoot string) (v1.Image, error) {
+	skip := examplesRoot
+	if skip == "" {
+		skip = filepath.Join(packageRoot, DefaultExamplesDir)
+	}
+	docs, err := readDocuments(packageRoot, skip)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the package manifests from: %s", packageRoot)
+	}
+	stream, err := packageStream(docs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to prepare the package stream from: %s", packageRoot)
+	}
+
+	img := empty.Image
+	cfgFile, err := img.ConfigFile()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the image config file")
+	}
+	cfg := cfgFile.Config
+	cfg.Labels = map[string]string{}
+
+	img, err = appendLayer(img, &cfg, StreamFile, PackageAnnotation, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	if examplesRoot != "" {
+		examples, err := readDocuments(examplesRoot, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the package examples from: %s", examplesRoot)
+		}
+		if len(examples) > 0 {
+			img, err = appendLayer(img, &cfg, ExamplesFile, ExamplesAnnotation, joinDocuments(examples))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	img, err = mutate.Config(img, cfg)
+	return img, errors.Wrap(err, "failed to set the package image config")
+}
+
+func appendLayer(img v1.Image, cfg *v1.Config, name, annotation string, content []byte) (v1.Image, error) {
+	layer, err := newLayer(name, content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the %q layer", annotation)
+	}
+	d, err := layer.Digest()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the digest of the %q layer", annotation)
+	}
+	cfg.Labels[label(d.String())] = annotation
+	img, err = mutate.Append(img, mutate.Addendum{
+		Layer: layer,
+		Annotations: map[string]string{
+			AnnotationKey: annotation,
+		},
+	})
+	return img, errors.Wrapf(err, "failed to append the %q layer", annotation)
+}
+
+func newLayer(name string, content []byte) (v1.Layer, error) {
+	buff := &bytes.Buffer{}
+	tw := tar.NewWriter(buff)
+	if err := tw.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: streamFileMode,
+		Size: int64(len(content)),
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to write the tar header for: %s", name)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return nil, errors.Wrapf(err, "failed to write the tar content for: %s", name)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close the tar writer")
+	}
+	return tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buff.Bytes())), nil
+	})
+}
+
+func label(digest string) string {
+	return AnnotationKey + ":" + digest
+}
+
+// packageStream returns the package stream with the single package metadata
+// document at the beginning.
+func packageStream(docs [][]byte) ([]byte, error) {
+	var meta []byte
+	objects := make([][]byte, 0, len(docs))
+	for _, d := range docs {
+		isMeta, err := isMetadata(d)
+		if err != nil {
+			return nil, err
+		}
+		if !isMeta {
+			objects = append(objects, d)
+			continue
+		}
+		if meta != nil {
+			return nil, errors.New("package contains more than one package metadata")
+		}
+		meta = d
+	}
+	if meta == nil {
+		return nil, errors.New("package metadata not found")
+	}
+	return joinDocuments(append([][]byte{meta}, objects...)), nil
+}
+
+func isMetadata(doc []byte) (bool, error) {
+	obj := struct {
+		APIVersion string `json:"apiVersion"`
+	}{}
+	if err := k8syaml.Unmarshal(doc, &obj); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal the package manifest")
+	}
+	return strings.HasPrefix(obj.APIVersion, metaGroup+"/"), nil
+}
+
+func joinDocuments(docs [][]byte) []byte {
+	buff := &bytes.Buffer{}
+	for _, d := range docs {
+		buff.WriteString(yamlSeparator)
+		buff.Write(d)
+		if !bytes.HasSuffix(d, []byte("\n")) {
+			buff.WriteString("\n")
+		}
+	}
+	return buff.Bytes()
+}
+
+// readDocuments reads the non-empty YAML documents from the files under the
+// given root in lexical file order, skipping the given directory.
+func readDocuments(root, skip string) ([][]byte, error) {
+	var paths []string
+	if err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if skip != "" && filepath.Clean(path) == filepath.Clean(skip) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+			paths = append(paths, path)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var docs [][]byte
+	for _, p := range paths {
+		buff, err := os.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read file: %s", p)
+		}
+		r := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(buff)))
+		for {
+			d, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read YAML document from file: %s", p)
+			}
+			if len(bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(d), []byte("---")))) == 0 {
+				continue
+			}
+			docs = append(docs, d)
+		}
+	}
+	return docs, nil
+}
@@ -0,0 +1,273 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xpkg
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+)
+
+const (
+	testMeta = `apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: platform-ref-aws
+`
+	testComposition = `apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: cluster
+`
+	testExample = `apiVersion: aws.platformref.upbound.io/v1alpha1
+kind: Cluster
+metadata:
+  name: example
+`
+	testRef = "xpkg.upbound.io/upbound/platform-ref-aws:v0.7.0"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for p, content := range files {
+		p = filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+}
+
+func layerContents(t *testing.T, img v1.Image) map[string]string {
+	t.Helper()
+	layers, err := img.Layers()
+	if err != nil {
+		t.Fatalf("Failed to get the image layers: %v", err)
+	}
+	contents := map[string]string{}
+	for _, l := range layers {
+		rc, err := l.Uncompressed()
+		if err != nil {
+			t.Fatalf("Failed to read the layer: %v", err)
+		}
+		tr := tar.NewReader(rc)
+		for {
+			h, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Failed to read the layer tarball: %v", err)
+			}
+			buff, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatalf("Failed to read the layer file: %v", err)
+			}
+			contents[h.Name] = string(buff)
+		}
+		_ = rc.Close()
+	}
+	return contents
+}
+
+func TestBuild(t *testing.T) {
+	type want struct {
+		contents map[string]string
+		labels   []string
+		err      bool
+	}
+	cases := map[string]struct {
+		files    map[string]string
+		examples bool
+		want     want
+	}{
+		"PackageWithExamples": {
+			files: map[string]string{
+				"apis/cluster/composition.yaml": testComposition,
+				"crossplane.yaml":               testMeta,
+				"examples/cluster.yaml":         testExample,
+			},
+			examples: true,
+			want: want{
+				contents: map[string]string{
+					StreamFile:   yamlSeparator + testMeta + yamlSeparator + testComposition,
+					ExamplesFile: yamlSeparator + testExample,
+				},
+				labels: []string{PackageAnnotation, ExamplesAnnotation},
+			},
+		},
+		"PackageWithoutExamples": {
+			files: map[string]string{
+				"crossplane.yaml": testMeta + "---\n" + testComposition,
+			},
+			want: want{
+				contents: map[string]string{
+					StreamFile: yamlSeparator + testMeta + yamlSeparator + testComposition,
+				},
+				labels: []string{PackageAnnotation},
+			},
+		},
+		"ExamplesWithoutExamplesRoot": {
+			files: map[string]string{
+				"crossplane.yaml":       testMeta,
+				"examples/cluster.yaml": testExample,
+			},
+			want: want{
+				contents: map[string]string{
+					StreamFile: yamlSeparator + testMeta,
+				},
+				labels: []string{PackageAnnotation},
+			},
+		},
+		"MissingMetadata": {
+			files: map[string]string{
+				"composition.yaml": testComposition,
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"MultipleMetadata": {
+			files: map[string]string{
+				"crossplane.yaml": testMeta,
+				"other.yaml":      testMeta,
+			},
+			want: want{
+				err: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, tc.files)
+			var examplesRoot string
+			if tc.examples {
+				examplesRoot = filepath.Join(root, DefaultExamplesDir)
+			}
+			img, err := Build(root, examplesRoot)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\nBuild(...): want error: %v, got: %v", tc.want.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.contents, layerContents(t, img)); diff != "" {
+				t.Errorf("\nBuild(...): -want contents, +got contents:\n%s", diff)
+			}
+			cfg, err := img.ConfigFile()
+			if err != nil {
+				t.Fatalf("Failed to get the image config: %v", err)
+			}
+			var labels []string
+			layers, err := img.Layers()
+			if err != nil {
+				t.Fatalf("Failed to get the image layers: %v", err)
+			}
+			for _, l := range layers {
+				d, err := l.Digest()
+				if err != nil {
+					t.Fatalf("Failed to get the layer digest: %v", err)
+				}
+				labels = append(labels, cfg.Config.Labels[label(d.String())])
+			}
+			if diff := cmp.Diff(tc.want.labels, labels); diff != "" {
+				t.Errorf("\nBuild(...): -want labels, +got labels:\n%s", diff)
+			}
+		})
+	}
+}
+
+type fakeExecutor struct {
+	steps []string
+}
+
+func (f *fakeExecutor) Init(_ map[string]any) error {
+	return nil
+}
+
+func (f *fakeExecutor) Step(s migration.Step, _ map[string]any) error {
+	f.steps = append(f.steps, s.Name)
+	return nil
+}
+
+func (f *fakeExecutor) Destroy() error {
+	return nil
+}
+
+func TestExecutor(t *testing.T) {
+	planDir := t.TempDir()
+	root := filepath.Join(planDir, "package")
+	writeFiles(t, root, map[string]string{
+		"crossplane.yaml":       testMeta + yamlSeparator + testComposition,
+		"examples/cluster.yaml": testExample,
+	})
+
+	build := migration.Step{Name: "build-configuration"}
+	SetBuildStep(&build, "package", "package/examples", "package.xpkg", testRef)
+	push := migration.Step{Name: "push-configuration"}
+	SetPushStep(&push, "package.xpkg", testRef, "layout")
+	other := migration.Step{Name: "other", Type: migration.StepTypeExec}
+
+	delegate := &fakeExecutor{}
+	e := NewExecutor(delegate, WithWorkingDir(planDir))
+	for _, s := range []migration.Step{build, push, other} {
+		if err := e.Step(s, nil); err != nil {
+			t.Fatalf("\nStep(%q): unexpected error: %v", s.Name, err)
+		}
+	}
+	if diff := cmp.Diff([]string{"other"}, delegate.steps); diff != "" {
+		t.Errorf("\nStep(...): -want delegated steps, +got delegated steps:\n%s", diff)
+	}
+
+	p, err := layout.FromPath(filepath.Join(planDir, "layout"))
+	if err != nil {
+		t.Fatalf("Failed to read the OCI image layout: %v", err)
+	}
+	idx, err := p.ImageIndex()
+	if err != nil {
+		t.Fatalf("Failed to read the OCI image index: %v", err)
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		t.Fatalf("Failed to read the OCI index manifest: %v", err)
+	}
+	if len(m.Manifests) != 1 {
+		t.Fatalf("\nStep(...): want 1 image in the OCI image layout, got: %d", len(m.Manifests))
+	}
+	if diff := cmp.Diff(testRef, m.Manifests[0].Annotations[annotationRefName]); diff != "" {
+		t.Errorf("\nStep(...): -want reference, +got reference:\n%s", diff)
+	}
+	img, err := p.Image(m.Manifests[0].Digest)
+	if err != nil {
+		t.Fatalf("Failed to read the image from the OCI image layout: %v", err)
+	}
+	want := map[string]string{
+		StreamFile:   yamlSeparator + testMeta + yamlSeparator + testComposition,
+		ExamplesFile: yamlSeparator + testExample,
+	}
+	if diff := cmp.Diff(want, layerContents(t, img)); diff != "" {
+		t.Errorf("\nStep(...): -want contents, +got contents:\n%s", diff)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xpkg
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/crossplane/upjet/pkg/migration"
+	"github.com/pkg/errors"
+)
+
+const (
+	// StepTypeBuild is the type of the steps building a Configuration
+	// package in-process.
+	StepTypeBuild migration.StepType = "BuildPackage"
+	// StepTypePush is the type of the steps pushing a Configuration
+	// package in-process, either to an OCI registry or to an OCI image
+	// layout.
+	StepTypePush migration.StepType = "PushPackage"
+
+	argPackageRoot  = "package-root"
+	argExamplesRoot = "examples-root"
+	argPackage      = "package"
+	argReference    = "reference"
+	argOCILayout    = "oci-layout"
+
+	errStepFailedFmt = "failed to execute the step %q"
+)
+
+// SetBuildStep sets the given step as a native build step building the
+// package from the given package and examples roots, and storing it at the
+// given path tagged with the given reference.
+func SetBuildStep(s *migration.Step, packageRoot, examplesRoot, pkgPath, ref string) {
+	s.Type = StepTypeBuild
+	s.Exec = &migration.ExecStep{
+		Args: []string{
+			stepArg(argPackageRoot, packageRoot),
+			stepArg(argExamplesRoot, examplesRoot),
+			stepArg(argPackage, pkgPath),
+			stepArg(argReference, ref),
+		},
+	}
+}
+
+// SetPushStep sets the given step as a native push step pushing the package
+// at the given path to the OCI registry with the given reference. If an OCI
+// image layout directory is specified, the package is written into that
+// layout instead.
+func SetPushStep(s *migration.Step, pkgPath, ref, ociLayout string) {
+	s.Type = StepTypePush
+	s.Exec = &migration.ExecStep{
+		Args: []string{
+			stepArg(argPackage, pkgPath),
+			stepArg(argReference, ref),
+		},
+	}
+	if ociLayout != "" {
+		s.Exec.Args = append(s.Exec.Args, stepArg(argOCILayout, ociLayout))
+	}
+}
+
+func stepArg(key, value string) string {
+	return fmt.Sprintf("--%s=%s", key, value)
+}
+
+func parseStepArgs(s migration.Step) (map[string]string, error) {
+	if s.Exec == nil {
+		return nil, errors.Errorf("step %q does not have any arguments", s.Name)
+	}
+	args := make(map[string]string, len(s.Exec.Args))
+	for _, a := range s.Exec.Args {
+		kv := strings.SplitN(strings.TrimPrefix(a, "--"), "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid argument %q for step %q", a, s.Name)
+		}
+		args[kv[0]] = kv[1]
+	}
+	return args, nil
+}
+
+var _ migration.Executor = &executor{}
+
+// executor executes the native package steps and delegates all the other
+// steps to the wrapped executor.
+type executor struct {
+	delegate migration.Executor
+	cwd      string
+}
+
+// ExecutorOption allows you to configure the package executor.
+type ExecutorOption func(e *executor)
+
+// WithWorkingDir sets the directory the relative paths in the step arguments
+// are resolved against.
+func WithWorkingDir(dir string) ExecutorOption {
+	return func(e *executor) {
+		e.cwd = dir
+	}
+}
+
+// NewExecutor returns a new executor that builds and pushes the packages
+// in-process and delegates the other steps to the given executor.
+func NewExecutor(delegate migration.Executor, opts ...ExecutorOption) migration.Executor {
+	e := &executor{
+		delegate: delegate,
+	}
+	for _, f := range opts {
+		f(e)
+	}
+	return e
+}
+
+func (e *executor) Init(config map[string]any) error {
+	return e.delegate.Init(config)
+}
+
+func (e *executor) Step(s migration.Step, ctx map[string]any) error {
+	switch s.Type {
+	case StepTypeBuild:
+		return errors.Wrapf(e.build(s), errStepFailedFmt, s.Name)
+	case StepTypePush:
+		return errors.Wrapf(e.push(s), errStepFailedFmt, s.Name)
+	default:
+		return e.delegate.Step(s, ctx)
+	}
+}
+
+func (e *executor) Destroy() error {
+	return e.delegate.Destroy()
+}
+
+func (e *executor) build(s migration.Step) error {
+	args, err := parseStepArgs(s)
+	if err != nil {
+		return err
+	}
+	examplesRoot := args[argExamplesRoot]
+	if examplesRoot != "" {
+		examplesRoot = e.path(examplesRoot)
+	}
+	img, err := Build(e.path(args[argPackageRoot]), examplesRoot)
+	if err != nil {
+		return err
+	}
+	return WriteFile(img, args[argReference], e.path(args[argPackage]))
+}
+
+func (e *executor) push(s migration.Step) error {
+	args, err := parseStepArgs(s)
+	if err != nil {
+		return err
+	}
+	img, err := ReadFile(e.path(args[argPackage]))
+	if err != nil {
+		return err
+	}
+	if l := args[argOCILayout]; l != "" {
+		return WriteLayout(img, args[argReference], e.path(l))
+	}
+	return Push(img, args[argReference])
+}
+
+func (e *executor) path(p string) string {
+	if e.cwd == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(e.cwd, p)
+}
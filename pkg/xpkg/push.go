@@ -0,0 +1,78 @@
+// Copyright 2023 Upbound Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xpkg
+
+import (
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/pkg/errors"
+)
+
+const (
+	// annotationRefName is the OCI image layout annotation for the
+	// reference of an image.
+	annotationRefName = "org.opencontainers.image.ref.name"
+)
+
+// WriteFile writes the given package image as a tarball to the specified
+// path, tagged with the given reference.
+func WriteFile(img v1.Image, ref, path string) error {
+	r, err := name.ParseReference(ref, name.WithDefaultRegistry(DefaultRegistry))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the package reference: %s", ref)
+	}
+	return errors.Wrapf(tarball.WriteToFile(path, r, img), "failed to write the package to file: %s", path)
+}
+
+// ReadFile reads a package image from the tarball at the given path.
+func ReadFile(path string) (v1.Image, error) {
+	img, err := tarball.ImageFromPath(path, nil)
+	return img, errors.Wrapf(err, "failed to read the package from file: %s", path)
+}
+
+// Push pushes the given package image to the OCI registry with the given
+// reference. The registry credentials are read from the docker config.
+func Push(img v1.Image, ref string) error {
+	r, err := name.ParseReference(ref, name.WithDefaultRegistry(DefaultRegistry))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the package reference: %s", ref)
+	}
+	return errors.Wrapf(remote.Write(r, img, remote.WithAuthFromKeychain(authn.DefaultKeychain)), "failed to push the package: %s", ref)
+}
+
+// WriteLayout writes the given package image into the OCI image layout at the
+// given directory, annotated with the given reference. The layout is created
+// if it does not exist.
+func WriteLayout(img v1.Image, ref, dir string) error {
+	p, err := layout.FromPath(dir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return errors.Wrapf(err, "failed to read the OCI image layout: %s", dir)
+		}
+		if p, err = layout.Write(dir, empty.Index); err != nil {
+			return errors.Wrapf(err, "failed to initialize the OCI image layout: %s", dir)
+		}
+	}
+	return errors.Wrapf(p.AppendImage(img, layout.WithAnnotations(map[string]string{
+		annotationRefName: ref,
+	})), "failed to write the package to the OCI image layout: %s", dir)
+}